@@ -0,0 +1,132 @@
+package xerrors
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// WithAttrs return an error that contains values given as [slog.Attr], preserving their kinds and groups.
+// As with [slog], empty attributes and groups are ignored and the attributes of a group with an empty key are inlined.
+//
+//	err = xerrors.Join(err, xerrors.WithAttrs(slog.Group("user", slog.String("id", id), slog.Int("age", age))))
+func WithAttrs(attrs ...slog.Attr) error {
+	err := newAttrValues(attrs)
+	created(CreatedByValue, err)
+	return err
+}
+
+// newAttrValues creates the error of WithAttrs without calling the hooks, for the constructors joining values.
+func newAttrValues(attrs []slog.Attr) *attrValues {
+	return &attrValues{
+		attrs: appendAttrs(make([]slog.Attr, 0, len(attrs)), attrs),
+	}
+}
+
+// appendAttrs appends attrs to dst, dropping the empty attributes and groups and inlining the groups with an empty key.
+func appendAttrs(dst, attrs []slog.Attr) []slog.Attr {
+	for _, a := range attrs {
+		switch {
+		case a.Equal(slog.Attr{}):
+		case a.Value.Kind() == slog.KindGroup && len(a.Value.Group()) == 0:
+		case a.Key == "" && a.Value.Kind() == slog.KindGroup:
+			dst = appendAttrs(dst, a.Value.Group())
+		default:
+			dst = append(dst, a)
+		}
+	}
+	return dst
+}
+
+type attrValues struct {
+	attrs []slog.Attr
+}
+
+// Value implements MultiValuer, the groups are converted to map[string]any.
+func (err *attrValues) Value() map[string]any {
+	return attrsMap(err.attrs)
+}
+
+func (err *attrValues) orderedKeys() []string {
+	keys := make([]string, 0, len(err.attrs))
+	seen := make(map[string]struct{}, len(err.attrs))
+	for _, a := range err.attrs {
+		if _, ok := seen[a.Key]; ok {
+			continue
+		}
+		seen[a.Key] = struct{}{}
+		keys = append(keys, a.Key)
+	}
+	return keys
+}
+
+// LogValue implements the [slog.LogValuer] interface
+// it is our main point of entry to format the error as an attribute of a [slog.Record].
+func (err *attrValues) LogValue() slog.Value {
+	return logValue(err)
+}
+
+// Format implements the [fmt.Formatter] interface
+// it is our main point of entry to format the error using the [fmt] package.
+func (err *attrValues) Format(s fmt.State, verb rune) {
+	format(err, s, verb)
+}
+
+func (err *attrValues) Error() string {
+	return stringify(err)
+}
+
+func (err *attrValues) message(verbose bool) string {
+	if !verbose {
+		return ""
+	}
+	keyValuePairs := make([]string, 0, len(err.attrs))
+	for _, a := range err.attrs {
+		keyValuePairs = append(keyValuePairs, fmt.Sprintf("%s: \"%v\"", a.Key, a.Value))
+	}
+
+	return "values: [" + strings.Join(keyValuePairs, " ") + "]"
+}
+
+func attrsMap(attrs []slog.Attr) map[string]any {
+	m := make(map[string]any, len(attrs))
+	for _, a := range attrs {
+		if a.Value.Kind() == slog.KindGroup {
+			m[a.Key] = attrsMap(a.Value.Group())
+			continue
+		}
+		m[a.Key] = a.Value.Any()
+	}
+	return m
+}
+
+// Attrs returns the values associated to an error as [slog.Attr], in the order of the error chain.
+// The attributes given to WithAttrs are returned as is, the other values are converted with [slog.Any].
+// Like Values, only the first value of a key is kept.
+func Attrs(err error) []slog.Attr {
+	errors := FlattenErrors(err)
+	var attrs []slog.Attr
+	seen := make(map[string]struct{})
+	for i := range errors {
+		for _, a := range errorAttrs(errors[i]) {
+			if _, ok := seen[a.Key]; ok {
+				continue
+			}
+			seen[a.Key] = struct{}{}
+			attrs = append(attrs, a)
+		}
+	}
+	return attrs
+}
+
+// errorAttrs returns the values held by err itself as [slog.Attr], in the order they are rendered.
+func errorAttrs(err error) []slog.Attr {
+	if a, ok := err.(*attrValues); ok {
+		return a.attrs
+	}
+	var attrs []slog.Attr
+	errorValues(err, func(key string, v any) {
+		attrs = append(attrs, slog.Any(key, v))
+	})
+	return attrs
+}
@@ -0,0 +1,97 @@
+package xerrors
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithAttrs(t *testing.T) {
+	for name, test := range map[string]struct {
+		attrs        []slog.Attr
+		wantMessage  string
+		wantValues   map[string]any
+		wantAttrKeys []string
+	}{
+		"flat": {
+			attrs:        []slog.Attr{slog.String("user", "bob"), slog.Int("age", 42)},
+			wantMessage:  `values: [user: "bob" age: "42"]`,
+			wantValues:   map[string]any{"user": "bob", "age": int64(42)},
+			wantAttrKeys: []string{"user", "age"},
+		},
+		"group": {
+			attrs:        []slog.Attr{slog.Group("user", slog.String("id", "bob"), slog.Int("age", 42))},
+			wantMessage:  `values: [user: "[id=bob age=42]"]`,
+			wantValues:   map[string]any{"user": map[string]any{"id": "bob", "age": int64(42)}},
+			wantAttrKeys: []string{"user"},
+		},
+		"empty": {
+			attrs:        []slog.Attr{{}, slog.String("user", "bob"), slog.Group("empty")},
+			wantMessage:  `values: [user: "bob"]`,
+			wantValues:   map[string]any{"user": "bob"},
+			wantAttrKeys: []string{"user"},
+		},
+		"inline_group": {
+			attrs:        []slog.Attr{slog.Group("", slog.String("user", "bob"), slog.Int("age", 42))},
+			wantMessage:  `values: [user: "bob" age: "42"]`,
+			wantValues:   map[string]any{"user": "bob", "age": int64(42)},
+			wantAttrKeys: []string{"user", "age"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := WithAttrs(test.attrs...)
+			assert.Equal(t, test.wantMessage, fmt.Sprintf("%+v", err))
+			assert.Equal(t, test.wantValues, Values(err))
+
+			attrs := Attrs(err)
+			keys := make([]string, 0, len(attrs))
+			for _, a := range attrs {
+				keys = append(keys, a.Key)
+			}
+			assert.Equal(t, test.wantAttrKeys, keys)
+		})
+	}
+}
+
+func TestAttrs(t *testing.T) {
+	err := Join(New("error"),
+		WithAttrs(slog.Group("user", slog.String("id", "bob")), slog.Duration("elapsed", time.Second)),
+		WithValue("status", 404),
+		WithValue("elapsed", "ignored"),
+	)
+
+	attrs := Attrs(err)
+	require.Len(t, attrs, 3)
+	assert.Equal(t, "user", attrs[0].Key)
+	assert.Equal(t, slog.KindGroup, attrs[0].Value.Kind())
+	assert.Equal(t, slog.KindDuration, attrs[1].Value.Kind())
+	assert.Equal(t, slog.Int("status", 404), attrs[2])
+
+	assert.Nil(t, Attrs(New("error")))
+}
+
+func TestAttrsLogValue(t *testing.T) {
+	err := Join(New("error"), WithAttrs(slog.Group("user", slog.String("id", "bob"), slog.Int("age", 42))), WithValue("status", 404))
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	logger.Info("test", slog.Any("error", err))
+
+	var m struct {
+		Error struct {
+			Values json.RawMessage `json:"values"`
+		} `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &m))
+	assert.Equal(t, `{"user":{"id":"bob","age":42},"status":404}`, string(m.Error.Values))
+
+	info := Info(err)
+	require.Len(t, info.OrderedValues, 2)
+	assert.Equal(t, slog.KindGroup, info.OrderedValues[0].Value.Kind())
+}
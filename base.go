@@ -2,6 +2,7 @@ package xerrors
 
 import (
 	std_errors "errors"
+	"reflect"
 )
 
 func newErrorString(text string) error {
@@ -17,9 +18,30 @@ func (e *errorString) Error() string {
 }
 
 // New returns a newErrorString error with a message and a stack.
+// An error created while initializing a package, such as a package-level variable, is registered as a sentinel,
+// see RegisterSentinel.
 func New(msg string) error {
-	err := newErrorString(msg)
-	err = withStack(err, 2)
+	err := withStack(newErrorString(msg), 2)
+	if initializing(err.(*stack).callers) {
+		RegisterSentinel(err)
+	}
+	created(CreatedByNew, err)
+	return err
+}
+
+// ErrorOrNil returns nil if err is nil or holds a nil pointer, err otherwise.
+// It prevents a nil pointer of a custom error type from being returned as a non-nil error.
+func ErrorOrNil(err error) error {
+	if err == nil {
+		return nil
+	}
+	v := reflect.ValueOf(err)
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+	}
 	return err
 }
 
@@ -37,3 +59,19 @@ func Is(err, target error) bool {
 func Unwrap(err error) error {
 	return std_errors.Unwrap(err)
 }
+
+// Cause returns the root cause of err, following the Cause method of github.com/pkg/errors,
+// the main error of the joined errors and the errors unwrapped by Unwrap.
+func Cause(err error) error {
+	for err != nil {
+		switch et := err.(type) {
+		case interface{ Cause() error }:
+			err = et.Cause()
+		case interface{ Unwrap() error }:
+			err = et.Unwrap()
+		default:
+			return err
+		}
+	}
+	return err
+}
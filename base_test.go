@@ -28,6 +28,29 @@ func TestIs(t *testing.T) {
 	assert.True(t, Is(err, io.EOF))
 }
 
+func TestErrorOrNil(t *testing.T) {
+	var nilPtr *net.ParseError
+	err := New("error")
+	for name, test := range map[string]struct {
+		err  error
+		want error
+	}{
+		"nil":       {err: nil, want: nil},
+		"typed_nil": {err: nilPtr, want: nil},
+		"error":     {err: err, want: err},
+		"value":     {err: io.EOF, want: io.EOF},
+	} {
+		t.Run(name, func(t *testing.T) {
+			got := ErrorOrNil(test.err)
+			if test.want == nil {
+				assert.Nil(t, got)
+				return
+			}
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
 func TestAs(t *testing.T) {
 	err := &net.ParseError{
 		Type: "_type",
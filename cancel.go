@@ -0,0 +1,50 @@
+package xerrors
+
+import (
+	"context"
+)
+
+// CancelWithError cancels a context created by [context.WithCancelCause] with err as its cause,
+// err being wrapped with a stack trace of the cancellation site so that the logs show who cancelled the context.
+// The cause is context.Canceled if err is nil.
+func CancelWithError(cancel context.CancelCauseFunc, err error) {
+	cancel(withStack(err, 2))
+}
+
+// WithCancelError works like [context.WithCancelCause],
+// the returned cancel function capturing the stack trace of the cancellation site like CancelWithError.
+func WithCancelError(parent context.Context) (context.Context, func(err error)) {
+	ctx, cancel := context.WithCancelCause(parent)
+	return ctx, func(err error) {
+		cancel(withStack(err, 2))
+	}
+}
+
+// FromContext returns the error of a done context joined with its cause, nil if ctx is not done.
+// It is equivalent to Join(ctx.Err(), context.Cause(ctx)), but the stack trace of the error is the one of the cause
+// when it has one, such as the stack trace of the cancellation site captured by CancelWithError.
+//
+// The error is expected when the cause is, a context canceled because of an unexpected error is not, see IsExpected.
+func FromContext(ctx context.Context) error {
+	err := ctx.Err()
+	if err == nil {
+		return nil
+	}
+
+	cause := context.Cause(ctx)
+	if cause == nil || cause == err {
+		e := join(3, err)
+		created(CreatedByJoin, e)
+		return e
+	}
+
+	e := &joinError{
+		err:  err,
+		errs: []error{cause},
+	}
+	if !hasStack(cause) {
+		e.err = withStack(err, 2)
+	}
+	created(CreatedByJoin, e)
+	return e
+}
@@ -0,0 +1,91 @@
+package xerrors
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromContext(t *testing.T) {
+	for name, test := range map[string]struct {
+		cancel        func(cancel context.CancelCauseFunc)
+		wantMessage   string
+		wantIs        []error
+		wantFunction  string
+		wantExpected  bool
+		wantErrorsLen int
+	}{
+		"canceled": {
+			cancel:       func(cancel context.CancelCauseFunc) { cancel(nil) },
+			wantMessage:  "context canceled",
+			wantIs:       []error{context.Canceled},
+			wantFunction: "github.com/emilien-puget/xerrors.TestFromContext.func5",
+			wantExpected: true,
+		},
+		"cause": {
+			cancel:       func(cancel context.CancelCauseFunc) { cancel(io.EOF) },
+			wantMessage:  "context canceled: EOF",
+			wantIs:       []error{context.Canceled, io.EOF},
+			wantFunction: "github.com/emilien-puget/xerrors.TestFromContext.func5",
+		},
+		"cancel_with_error": {
+			cancel:       func(cancel context.CancelCauseFunc) { CancelWithError(cancel, io.EOF) },
+			wantMessage:  "context canceled: EOF",
+			wantIs:       []error{context.Canceled, io.EOF},
+			wantFunction: "github.com/emilien-puget/xerrors.TestFromContext.func3",
+		},
+		"cancel_with_expected_error": {
+			cancel:       func(cancel context.CancelCauseFunc) { CancelWithError(cancel, Expected(io.EOF)) },
+			wantMessage:  "context canceled: EOF",
+			wantIs:       []error{context.Canceled, io.EOF},
+			wantFunction: "github.com/emilien-puget/xerrors.TestFromContext.func4",
+			wantExpected: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancelCause(context.Background())
+			assert.NoError(t, FromContext(ctx))
+			test.cancel(cancel)
+
+			err := FromContext(ctx)
+			require.Error(t, err)
+			assert.Equal(t, test.wantMessage, err.Error())
+			for _, target := range test.wantIs {
+				assert.ErrorIs(t, err, target)
+			}
+			assert.Equal(t, test.wantExpected, IsExpected(err))
+
+			info := Info(err)
+			require.NotEmpty(t, info.Frames)
+			assert.Equal(t, test.wantFunction, info.Frames[0].Function)
+		})
+	}
+}
+
+func TestWithCancelError(t *testing.T) {
+	ctx, cancel := WithCancelError(context.Background())
+	cancel(errors.New("shutting down"))
+
+	err := FromContext(ctx)
+	assert.Equal(t, "context canceled: shutting down", err.Error())
+	assert.False(t, IsExpected(err))
+
+	info := Info(err)
+	require.NotEmpty(t, info.Frames)
+	assert.Equal(t, "github.com/emilien-puget/xerrors.TestWithCancelError", info.Frames[0].Function)
+}
+
+func TestFromContextDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+	<-ctx.Done()
+
+	err := FromContext(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, "context deadline exceeded", err.Error())
+	assert.False(t, IsExpected(err))
+}
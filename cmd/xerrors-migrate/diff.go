@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines around the changes of a hunk.
+const diffContext = 3
+
+type opKind byte
+
+const (
+	opEqual  opKind = ' '
+	opDelete opKind = '-'
+	opInsert opKind = '+'
+)
+
+type op struct {
+	kind opKind
+	line string
+	// a and b are the indexes of the line in the old and the new text.
+	a, b int
+}
+
+// unifiedDiff returns the unified diff between the old and the new content of the file name, empty if they are equal.
+func unifiedDiff(name string, oldContent, newContent []byte) string {
+	a, b := splitLines(string(oldContent)), splitLines(string(newContent))
+	ops := diffLines(a, b)
+
+	var sb strings.Builder
+	for start := 0; start < len(ops); {
+		// Find the next change.
+		for start < len(ops) && ops[start].kind == opEqual {
+			start++
+		}
+		if start == len(ops) {
+			break
+		}
+		from := max(start-diffContext, 0)
+
+		// Extend the hunk until the changes are separated by more than twice the context.
+		end := start
+		for i := start; i < len(ops); i++ {
+			if ops[i].kind != opEqual {
+				end = i + 1
+				continue
+			}
+			if i-end >= 2*diffContext {
+				break
+			}
+		}
+		to := min(end+diffContext, len(ops))
+
+		if sb.Len() == 0 {
+			_, _ = fmt.Fprintf(&sb, "--- %s\n+++ %s\n", name, name)
+		}
+		writeHunk(&sb, ops[from:to])
+		start = to
+	}
+	return sb.String()
+}
+
+func writeHunk(sb *strings.Builder, ops []op) {
+	aStart, bStart := ops[0].a, ops[0].b
+	aLen, bLen := 0, 0
+	for _, o := range ops {
+		switch o.kind {
+		case opEqual:
+			aLen++
+			bLen++
+		case opDelete:
+			aLen++
+		case opInsert:
+			bLen++
+		}
+	}
+	_, _ = fmt.Fprintf(sb, "@@ -%d,%d +%d,%d @@\n", aStart+1, aLen, bStart+1, bLen)
+	for _, o := range ops {
+		sb.WriteByte(byte(o.kind))
+		sb.WriteString(o.line)
+		sb.WriteByte('\n')
+	}
+}
+
+// diffLines returns the edit script turning a into b, computed from their longest common subsequence.
+func diffLines(a, b []string) []op {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	ops := make([]op, 0, max(len(a), len(b)))
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			ops = append(ops, op{kind: opEqual, line: a[i], a: i, b: j})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, op{kind: opDelete, line: a[i], a: i, b: j})
+			i++
+		default:
+			ops = append(ops, op{kind: opInsert, line: b[j], a: i, b: j})
+			j++
+		}
+	}
+	return ops
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
@@ -0,0 +1,16 @@
+module github.com/emilien-puget/xerrors/cmd/xerrors-migrate
+
+go 1.23.0
+
+require (
+	github.com/stretchr/testify v1.9.0
+	golang.org/x/tools v0.34.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/mod v0.25.0 // indirect
+	golang.org/x/sync v0.15.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
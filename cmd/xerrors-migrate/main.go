@@ -0,0 +1,122 @@
+// Command xerrors-migrate rewrites the usages of github.com/pkg/errors and the wrapping calls to fmt.Errorf
+// into their xerrors equivalents.
+//
+// Usage:
+//
+//	xerrors-migrate [-n] path...
+//
+// Each path is a Go file or a directory walked recursively, vendor and testdata directories excluded,
+// "./..." being accepted as well.
+// The files are rewritten in place, unless -n is set, in which case a diff of the changes is printed.
+// The sites that cannot be converted are reported on the standard error.
+//
+// errors.Wrap and errors.WithMessage are rewritten into xerrors.Wrap and xerrors.WithMessage, which keep the
+// "msg: err" rendering, and so is fmt.Errorf("msg: %w", err).
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/token"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	dryRun := flag.Bool("n", false, "dry run: print a diff of the changes instead of rewriting the files")
+	flag.Usage = func() {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "usage: xerrors-migrate [-n] path...\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	os.Exit(run(os.Stdout, os.Stderr, *dryRun, flag.Args()))
+}
+
+// run migrates the files found in paths, it returns the exit code of the command.
+func run(stdout, stderr io.Writer, dryRun bool, paths []string) int {
+	files, err := goFiles(paths)
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	code := 0
+	fset := token.NewFileSet()
+	for _, file := range files {
+		if err := migrateFile(stdout, stderr, fset, file, dryRun); err != nil {
+			_, _ = fmt.Fprintln(stderr, err)
+			code = 1
+		}
+	}
+	return code
+}
+
+func migrateFile(stdout, stderr io.Writer, fset *token.FileSet, file string, dryRun bool) error {
+	src, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	out, reports, err := migrate(fset, file, src)
+	if err != nil {
+		return err
+	}
+	for _, r := range reports {
+		_, _ = fmt.Fprintln(stderr, r)
+	}
+	if string(out) == string(src) {
+		return nil
+	}
+
+	if dryRun {
+		_, err = io.WriteString(stdout, unifiedDiff(filepath.ToSlash(file), src, out))
+		return err
+	}
+	return os.WriteFile(file, out, 0o644)
+}
+
+// goFiles returns the Go files found in paths.
+func goFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		// Directories are walked recursively, the ./... form of the go command is accepted for convenience.
+		path = strings.TrimSuffix(path, "/...")
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				name := d.Name()
+				if p != path && (name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".")) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if strings.HasSuffix(p, ".go") {
+				files = append(files, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
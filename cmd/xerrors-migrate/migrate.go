@@ -0,0 +1,330 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/imports"
+)
+
+const (
+	xerrorsPath   = "github.com/emilien-puget/xerrors"
+	pkgErrorsPath = "github.com/pkg/errors"
+)
+
+// report is a site that could not be converted.
+type report struct {
+	pos token.Position
+	msg string
+}
+
+func (r report) String() string {
+	return fmt.Sprintf("%s: %s", r.pos, r.msg)
+}
+
+// migrate rewrites the calls to github.com/pkg/errors and the wrapping calls to fmt.Errorf of a file into their
+// xerrors equivalents. It returns the rewritten source and the sites that could not be converted.
+func migrate(fset *token.FileSet, filename string, src []byte) ([]byte, []report, error) {
+	f, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	m := &migrator{
+		fset:      fset,
+		pkgErrors: importName(f, pkgErrorsPath),
+		fmt:       importName(f, "fmt"),
+	}
+	if m.pkgErrors == "" && m.fmt == "" {
+		return src, nil, nil
+	}
+	for _, imp := range f.Imports {
+		if name := importName(f, importPath(imp)); name == "xerrors" && importPath(imp) != xerrorsPath {
+			m.report(imp, fmt.Sprintf("the file imports %s as xerrors", importPath(imp)))
+			return src, m.reports, nil
+		}
+	}
+
+	astutil.Apply(f, func(c *astutil.Cursor) bool {
+		bin, ok := c.Node().(*ast.BinaryExpr)
+		if !ok {
+			return true
+		}
+		if repl := m.rewriteCauseComparison(bin); repl != nil {
+			c.Replace(repl)
+			m.changed = true
+		}
+		return true
+	}, func(c *astutil.Cursor) bool {
+		call, ok := c.Node().(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		if repl := m.rewrite(call); repl != nil {
+			c.Replace(repl)
+			m.changed = true
+		}
+		return true
+	})
+	if !m.changed {
+		return src, m.reports, nil
+	}
+
+	astutil.AddImport(fset, f, xerrorsPath)
+	if m.pkgErrors != "" && !astutil.UsesImport(f, pkgErrorsPath) {
+		astutil.DeleteImport(fset, f, pkgErrorsPath)
+	}
+	if m.needFmt {
+		astutil.AddImport(fset, f, "fmt")
+	} else if m.fmt != "" && !astutil.UsesImport(f, "fmt") {
+		astutil.DeleteImport(fset, f, "fmt")
+	}
+
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, f); err != nil {
+		return nil, nil, err
+	}
+	// Separate the standard library imports from the others, as goimports does.
+	out, err := imports.Process(filename, buf.Bytes(), &imports.Options{FormatOnly: true, Comments: true, TabIndent: true, TabWidth: 8})
+	if err != nil {
+		return nil, nil, err
+	}
+	return out, m.reports, nil
+}
+
+type migrator struct {
+	fset *token.FileSet
+	// pkgErrors and fmt are the names of the imports of github.com/pkg/errors and fmt, empty if not imported.
+	pkgErrors string
+	fmt       string
+
+	changed bool
+	needFmt bool
+	reports []report
+}
+
+func (m *migrator) report(node ast.Node, msg string) {
+	m.reports = append(m.reports, report{
+		pos: m.fset.Position(node.Pos()),
+		msg: msg,
+	})
+}
+
+// rewrite returns the replacement of call, nil if call is left untouched.
+func (m *migrator) rewrite(call *ast.CallExpr) ast.Expr {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return nil
+	}
+	id, ok := sel.X.(*ast.Ident)
+	if !ok || id.Obj != nil {
+		return nil
+	}
+
+	switch {
+	case m.pkgErrors != "" && id.Name == m.pkgErrors:
+		return m.rewritePkgErrors(call, sel.Sel.Name)
+	case m.fmt != "" && id.Name == m.fmt && sel.Sel.Name == "Errorf":
+		return m.rewriteErrorf(call)
+	}
+	return nil
+}
+
+func (m *migrator) rewritePkgErrors(call *ast.CallExpr, name string) ast.Expr {
+	args := call.Args
+	if call.Ellipsis.IsValid() && name != "Errorf" && name != "Wrapf" && name != "WithMessagef" {
+		m.report(call, fmt.Sprintf("cannot convert %s.%s called with a variadic argument", m.pkgErrors, name))
+		return nil
+	}
+
+	switch {
+	case name == "New" && len(args) == 1:
+		return m.xerrorsCall("New", args...)
+	case name == "Errorf" && len(args) >= 1:
+		return m.xerrorsCall("New", m.sprintf(call, args))
+	case (name == "Wrap" || name == "WithMessage") && len(args) == 2:
+		return m.xerrorsCall(name, args...)
+	case (name == "Wrapf" || name == "WithMessagef") && len(args) >= 2:
+		return m.xerrorsCall(strings.TrimSuffix(name, "f"), args[0], m.sprintf(call, args[1:]))
+	case name == "WithStack" && len(args) == 1:
+		return m.xerrorsCall("WithStack", args...)
+	case name == "Cause" || name == "Is" || name == "As" || name == "Unwrap":
+		return m.xerrorsCall(name, args...)
+	}
+
+	m.report(call, fmt.Sprintf("%s.%s has no xerrors equivalent", m.pkgErrors, name))
+	return nil
+}
+
+// rewriteCauseComparison converts errors.Cause(err) == target into xerrors.Is(err, target), and != into !xerrors.Is,
+// the root cause of an error created by xerrors being the error wrapped by its stack trace rather than the error itself.
+// It returns nil if bin is left untouched.
+func (m *migrator) rewriteCauseComparison(bin *ast.BinaryExpr) ast.Expr {
+	if m.pkgErrors == "" || (bin.Op != token.EQL && bin.Op != token.NEQ) {
+		return nil
+	}
+
+	errArg, target := m.causeArg(bin.X), bin.Y
+	if errArg == nil {
+		errArg, target = m.causeArg(bin.Y), bin.X
+	}
+	if errArg == nil {
+		return nil
+	}
+	if id, ok := target.(*ast.Ident); ok && id.Name == "nil" {
+		return nil
+	}
+
+	is := m.xerrorsCall("Is", errArg, target)
+	if bin.Op == token.NEQ {
+		return &ast.UnaryExpr{Op: token.NOT, X: is}
+	}
+	return is
+}
+
+// causeArg returns the argument of expr if it is a call to errors.Cause, nil otherwise.
+func (m *migrator) causeArg(expr ast.Expr) ast.Expr {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 || call.Ellipsis.IsValid() {
+		return nil
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Cause" {
+		return nil
+	}
+	if id, ok := sel.X.(*ast.Ident); !ok || id.Obj != nil || id.Name != m.pkgErrors {
+		return nil
+	}
+	return call.Args[0]
+}
+
+// rewriteErrorf converts fmt.Errorf("msg: %w", err) into xerrors.Wrap(err, "msg").
+func (m *migrator) rewriteErrorf(call *ast.CallExpr) ast.Expr {
+	if len(call.Args) == 0 {
+		return nil
+	}
+	lit, ok := call.Args[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		m.report(call, "cannot convert fmt.Errorf with a non-constant format")
+		return nil
+	}
+	format, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return nil
+	}
+
+	verbs, ok := parseVerbs(format)
+	if !ok {
+		if strings.Contains(format, "w") {
+			m.report(call, "cannot convert fmt.Errorf, the format is too complex")
+		}
+		return nil
+	}
+	wrapped := strings.Count(string(verbs), "w")
+	switch {
+	case wrapped == 0:
+		return nil
+	case wrapped > 1 || call.Ellipsis.IsValid() || len(verbs) != len(call.Args)-1:
+		m.report(call, "cannot convert fmt.Errorf, the format is too complex")
+		return nil
+	case verbs[len(verbs)-1] != 'w' || !strings.HasSuffix(format, "%w"):
+		m.report(call, "cannot convert fmt.Errorf, %w is not at the end of the format")
+		return nil
+	}
+
+	msg := strings.TrimSuffix(format, "%w")
+	if msg != "" && !strings.HasSuffix(msg, ": ") {
+		m.report(call, `cannot convert fmt.Errorf, %w is not preceded by ": "`)
+		return nil
+	}
+	msg = strings.TrimSuffix(msg, ": ")
+
+	last := len(call.Args) - 1
+	errArg := call.Args[last]
+	if msg == "" {
+		return m.xerrorsCall("Join", errArg)
+	}
+	if len(verbs) == 1 {
+		return m.xerrorsCall("Wrap", errArg, stringLit(strings.ReplaceAll(msg, "%%", "%")))
+	}
+	return m.xerrorsCall("Wrap", errArg, m.sprintf(nil, append([]ast.Expr{stringLit(msg)}, call.Args[1:last]...)))
+}
+
+func (m *migrator) xerrorsCall(name string, args ...ast.Expr) *ast.CallExpr {
+	return &ast.CallExpr{
+		Fun:  &ast.SelectorExpr{X: ast.NewIdent("xerrors"), Sel: ast.NewIdent(name)},
+		Args: args,
+	}
+}
+
+// sprintf returns a call to fmt.Sprintf with args, keeping the variadic argument of call if any.
+func (m *migrator) sprintf(call *ast.CallExpr, args []ast.Expr) *ast.CallExpr {
+	m.needFmt = true
+	fmtName := m.fmt
+	if fmtName == "" {
+		fmtName = "fmt"
+	}
+	sprintf := &ast.CallExpr{
+		Fun:  &ast.SelectorExpr{X: ast.NewIdent(fmtName), Sel: ast.NewIdent("Sprintf")},
+		Args: args,
+	}
+	if call != nil && call.Ellipsis.IsValid() {
+		sprintf.Ellipsis = 1
+	}
+	return sprintf
+}
+
+func stringLit(s string) *ast.BasicLit {
+	return &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(s)}
+}
+
+// parseVerbs returns the verbs of a format string, "%%" excluded.
+// ok is false if the format uses explicit argument indexes or '*', or is malformed.
+func parseVerbs(format string) (verbs []rune, ok bool) {
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		i++
+		for i < len(format) && strings.IndexByte("+-# 0123456789.", format[i]) >= 0 {
+			i++
+		}
+		if i >= len(format) || format[i] == '[' || format[i] == '*' {
+			return nil, false
+		}
+		if format[i] == '%' {
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(format[i:])
+		verbs = append(verbs, r)
+		i += size - 1
+	}
+	return verbs, true
+}
+
+// importName returns the name under which the package path is imported by f, empty if it is not imported.
+func importName(f *ast.File, path string) string {
+	for _, imp := range f.Imports {
+		if importPath(imp) != path {
+			continue
+		}
+		if imp.Name != nil {
+			return imp.Name.Name
+		}
+		return path[strings.LastIndex(path, "/")+1:]
+	}
+	return ""
+}
+
+func importPath(imp *ast.ImportSpec) string {
+	path, _ := strconv.Unquote(imp.Path.Value)
+	return path
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+func TestMigrate(t *testing.T) {
+	dirs, err := filepath.Glob("testdata/*")
+	require.NoError(t, err)
+
+	for _, dir := range dirs {
+		t.Run(filepath.Base(dir), func(t *testing.T) {
+			input := filepath.Join(dir, "input.go")
+			src, err := os.ReadFile(input)
+			require.NoError(t, err)
+
+			out, reports, err := migrate(token.NewFileSet(), input, src)
+			require.NoError(t, err)
+
+			var reportLines strings.Builder
+			for _, r := range reports {
+				reportLines.WriteString(r.String() + "\n")
+			}
+
+			goldenOut := filepath.Join(dir, "output.golden")
+			goldenReports := filepath.Join(dir, "reports.golden")
+			if *update {
+				require.NoError(t, os.WriteFile(goldenOut, out, 0o644))
+				require.NoError(t, os.WriteFile(goldenReports, []byte(reportLines.String()), 0o644))
+			}
+
+			wantOut, err := os.ReadFile(goldenOut)
+			require.NoError(t, err)
+			wantReports, err := os.ReadFile(goldenReports)
+			require.NoError(t, err)
+			assert.Equal(t, string(wantOut), string(out))
+			assert.Equal(t, string(wantReports), reportLines.String())
+		})
+	}
+}
+
+func TestParseVerbs(t *testing.T) {
+	for format, want := range map[string]string{
+		"reading: %w":         "w",
+		"100%% %s %5.2f: %+v": "sfv",
+		"%d items: %w":        "dw",
+	} {
+		verbs, ok := parseVerbs(format)
+		assert.True(t, ok, format)
+		assert.Equal(t, want, string(verbs), format)
+	}
+
+	for _, format := range []string{"%[1]w", "%*d", "trailing %"} {
+		_, ok := parseVerbs(format)
+		assert.False(t, ok, format)
+	}
+}
+
+func TestRunDryRun(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "errorf.go")
+	src, err := os.ReadFile("testdata/errorf/input.go")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(file, src, 0o644))
+
+	var stdout, stderr bytes.Buffer
+	code := run(&stdout, &stderr, true, []string{dir + "/..."})
+
+	assert.Equal(t, 0, code)
+	assert.Empty(t, stderr.String())
+	diff := stdout.String()
+	assert.True(t, strings.HasPrefix(diff, "--- "+filepath.ToSlash(file)+"\n+++ "+filepath.ToSlash(file)+"\n@@ "), diff)
+	assert.Contains(t, diff, "-\t\treturn fmt.Errorf(\"reading: %w\", io.EOF)\n")
+	assert.Contains(t, diff, "+\t\treturn xerrors.Wrap(io.EOF, \"reading\")\n")
+
+	got, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Equal(t, string(src), string(got), "a dry run must not rewrite the files")
+}
+
+func TestRunWrite(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "unsupported.go")
+	src, err := os.ReadFile("testdata/unsupported/input.go")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(file, src, 0o644))
+
+	var stdout, stderr bytes.Buffer
+	code := run(&stdout, &stderr, false, []string{file})
+
+	assert.Equal(t, 0, code)
+	assert.Empty(t, stdout.String())
+	assert.Contains(t, stderr.String(), "cannot convert fmt.Errorf with a non-constant format")
+
+	got, err := os.ReadFile(file)
+	require.NoError(t, err)
+	want, err := os.ReadFile("testdata/unsupported/output.golden")
+	require.NoError(t, err)
+	assert.Equal(t, string(want), string(got))
+}
+
+func TestRunMissingPath(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 1, run(&stdout, &stderr, false, []string{"does-not-exist"}))
+	assert.NotEmpty(t, stderr.String())
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	assert.Empty(t, unifiedDiff("f", []byte("a\nb\n"), []byte("a\nb\n")))
+	assert.Equal(t, "--- f\n+++ f\n@@ -1,3 +1,3 @@\n a\n-b\n+c\n d\n", unifiedDiff("f", []byte("a\nb\nd\n"), []byte("a\nc\nd\n")))
+}
@@ -0,0 +1,19 @@
+package errorf
+
+import (
+	"fmt"
+	"io"
+)
+
+func Read(name string, size int) error {
+	if size == 0 {
+		return fmt.Errorf("reading: %w", io.EOF)
+	}
+	if size < 0 {
+		return fmt.Errorf("reading %s with size %d: %w", name, size, io.EOF)
+	}
+	if size > 100 {
+		return fmt.Errorf("%w", io.ErrUnexpectedEOF)
+	}
+	return fmt.Errorf("100%% done: %w", io.ErrShortBuffer)
+}
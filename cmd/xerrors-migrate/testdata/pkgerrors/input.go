@@ -0,0 +1,42 @@
+package pkgerrors
+
+import (
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+var ErrNotFound = errors.New("not found")
+
+func Load(name string) error {
+	if name == "" {
+		return errors.WithStack(ErrNotFound)
+	}
+	if err := read(name); err != nil {
+		return errors.Wrap(err, "loading")
+	}
+	return errors.Wrapf(io.EOF, "loading %s", name)
+}
+
+func read(name string) error {
+	return errors.Errorf("cannot read %q", name)
+}
+
+func Save(err error) error {
+	return errors.WithMessagef(err, "saving %d", 42)
+}
+
+func IsNotFound(err error) bool {
+	return errors.Cause(err) == ErrNotFound
+}
+
+func IsOtherEOF(err error) bool {
+	return ErrNotFound != errors.Cause(err) && errors.Is(err, io.EOF)
+}
+
+func Root(err error) error {
+	if errors.Cause(err) != nil {
+		return errors.Cause(err)
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package unsupported
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+func Read(format string, args ...any) error {
+	if len(args) == 0 {
+		return fmt.Errorf(format, args...)
+	}
+	if len(args) == 1 {
+		return fmt.Errorf("%w: reading", io.EOF)
+	}
+	if len(args) == 2 {
+		return fmt.Errorf("reading %w and %w", io.EOF, io.ErrUnexpectedEOF)
+	}
+	err := errors.Wrap(io.EOF, "reading")
+	return errors.WithMessage(err, fmt.Sprintf("not wrapping %d", len(args)))
+}
+
+func Stack(err error) errors.StackTrace {
+	return errors.New("stack").(interface{ StackTrace() errors.StackTrace }).StackTrace()
+}
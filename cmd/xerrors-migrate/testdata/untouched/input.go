@@ -0,0 +1,12 @@
+package untouched
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrNotFound = errors.New("not found")
+
+func Describe(name string) error {
+	return fmt.Errorf("describing %s", name)
+}
@@ -0,0 +1,67 @@
+package xerrors
+
+import (
+	"context"
+	"sync"
+)
+
+// ContextExtractor returns the request-scoped values held by ctx that must be attached to an error.
+type ContextExtractor func(ctx context.Context) map[string]any
+
+var (
+	extractorsMu sync.RWMutex
+	extractors   []ContextExtractor
+)
+
+// RegisterContextExtractor registers an extractor used by NewCtx and JoinCtx.
+// When several extractors return the same key, the value of the first registered extractor is kept.
+func RegisterContextExtractor(extractor ContextExtractor) {
+	extractorsMu.Lock()
+	defer extractorsMu.Unlock()
+	extractors = append(extractors, extractor)
+}
+
+// NewCtx returns a new error with a message, a stack and the values extracted from ctx.
+func NewCtx(ctx context.Context, msg string) error {
+	err := withStack(newErrorString(msg), 2)
+	if vals := contextValues(ctx); vals != nil {
+		err = &joinError{
+			err:  err,
+			errs: []error{newMultiValue(vals)},
+		}
+	}
+	created(CreatedByNew, err)
+	return err
+}
+
+// JoinCtx works like Join and attaches the values extracted from ctx to the resulting error.
+func JoinCtx(ctx context.Context, ogErr error, errs ...any) error {
+	e := join(3, ogErr, errs...)
+	if e == nil {
+		return nil
+	}
+	if vals := contextValues(ctx); vals != nil {
+		e.errs = append(e.errs, newMultiValue(vals))
+	}
+	created(CreatedByJoin, e)
+	return e
+}
+
+func contextValues(ctx context.Context) map[string]any {
+	extractorsMu.RLock()
+	defer extractorsMu.RUnlock()
+
+	var vals map[string]any
+	for _, extractor := range extractors {
+		for k, v := range extractor(ctx) {
+			if vals == nil {
+				vals = make(map[string]any)
+			}
+			if _, ok := vals[k]; ok {
+				continue
+			}
+			vals[k] = v
+		}
+	}
+	return vals
+}
@@ -0,0 +1,70 @@
+package xerrors
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type ctxKey string
+
+func registerTestExtractor(t *testing.T, extractor ContextExtractor) {
+	t.Helper()
+	extractorsMu.Lock()
+	saved := extractors
+	extractors = nil
+	extractorsMu.Unlock()
+	t.Cleanup(func() {
+		extractorsMu.Lock()
+		extractors = saved
+		extractorsMu.Unlock()
+	})
+	RegisterContextExtractor(extractor)
+}
+
+func requestIDExtractor(ctx context.Context) map[string]any {
+	id, ok := ctx.Value(ctxKey("request_id")).(string)
+	if !ok {
+		return nil
+	}
+	return map[string]any{"request_id": id}
+}
+
+func TestNewCtx(t *testing.T) {
+	registerTestExtractor(t, requestIDExtractor)
+	ctx := context.WithValue(context.Background(), ctxKey("request_id"), "abc")
+
+	err := NewCtx(ctx, "error")
+	assert.Equal(t, "error", err.Error())
+	assert.Equal(t, map[string]any{"request_id": "abc"}, Values(err))
+	assert.Contains(t, fmt.Sprintf("%+v", err), `request_id: "abc"`)
+
+	frames := StackFrames(err).Frames()
+	assert.Equal(t, "github.com/emilien-puget/xerrors.TestNewCtx", frames[0].Function)
+}
+
+func TestNewCtxNoValues(t *testing.T) {
+	registerTestExtractor(t, requestIDExtractor)
+
+	err := NewCtx(context.Background(), "error")
+	assert.Equal(t, "error", err.Error())
+	assert.Empty(t, Values(err))
+}
+
+func TestJoinCtx(t *testing.T) {
+	registerTestExtractor(t, requestIDExtractor)
+	RegisterContextExtractor(func(ctx context.Context) map[string]any {
+		return map[string]any{"request_id": "overwritten", "tenant_id": 42}
+	})
+	ctx := context.WithValue(context.Background(), ctxKey("request_id"), "abc")
+
+	err := JoinCtx(ctx, errmy, "its a wrap")
+	assert.Equal(t, "plouf: its a wrap", err.Error())
+	assert.ErrorIs(t, err, errmy)
+
+	info := Info(err)
+	assert.Equal(t, map[string]any{"request_id": "abc", "tenant_id": 42}, info.Values)
+	assert.Contains(t, info.StackTraces[0], "github.com/emilien-puget/xerrors.TestJoinCtx")
+}
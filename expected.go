@@ -0,0 +1,114 @@
+package xerrors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+// ExpectedError is an interface that allows custom error types to report whether they are expected,
+// such as the validation errors caused by the clients of an API, as opposed to failures that deserve an alert.
+type ExpectedError interface {
+	// Expected reports whether the error is expected.
+	Expected() bool
+}
+
+// Expected returns err marked as expected: a benign error, such as a client mistake or a cancellation,
+// that is logged at a lower level and excluded from the error rate. Expected returns nil if err is nil.
+func Expected(err error) error {
+	if err == nil {
+		return nil
+	}
+	e := &expected{
+		err:      err,
+		expected: true,
+	}
+	created(CreatedByWrap, e)
+	return e
+}
+
+// IsExpected reports whether err is expected: the outermost error implementing ExpectedError along the chain of
+// the main errors decides, context.Canceled being expected.
+// Joined errors are expected when their main error is, every error given to errors.Join being a main error.
+// A context error joined with its cause, as returned by FromContext, is expected when the cause is.
+func IsExpected(err error) bool {
+	for err != nil {
+		if e, ok := err.(ExpectedError); ok {
+			return e.Expected()
+		}
+		if err == context.Canceled {
+			return true
+		}
+		switch u := err.(type) {
+		case *joinError:
+			if cause := contextCause(u); cause != nil {
+				return IsExpected(cause)
+			}
+			err = u.err
+		case interface{ Unwrap() error }:
+			err = u.Unwrap()
+		case interface{ Unwrap() []error }:
+			for _, e := range u.Unwrap() {
+				if IsExpected(e) {
+					return true
+				}
+			}
+			return false
+		default:
+			return false
+		}
+	}
+	return false
+}
+
+// contextCause returns the cause joined with a context error, the first joined error that is neither a message nor
+// a value, nil if the main error of err is not a context error.
+func contextCause(err *joinError) error {
+	if !errors.Is(err.err, context.Canceled) && !errors.Is(err.err, context.DeadlineExceeded) {
+		return nil
+	}
+	for _, e := range err.errs {
+		if _, ok := e.(*errorString); !ok && !isValue(e) {
+			return e
+		}
+	}
+	return nil
+}
+
+type expected struct {
+	err      error
+	expected bool
+}
+
+// Expected implements the ExpectedError interface.
+func (err *expected) Expected() bool {
+	return err.expected
+}
+
+func (err *expected) Unwrap() error {
+	return err.err
+}
+
+func (err *expected) Error() string {
+	return stringify(err)
+}
+
+func (err *expected) message(verbose bool) string {
+	if !verbose {
+		return ""
+	}
+	return fmt.Sprintf("\nexpected: %t", err.expected)
+}
+
+// LogValue implements the [slog.LogValuer] interface
+// it is our main point of entry to format the error as an attribute of a [slog.Record].
+func (err *expected) LogValue() slog.Value {
+	return logValue(err)
+}
+
+// Format implements the [fmt.Formatter] interface
+// it is our main point of entry to format the error using the [fmt] package.
+func (err *expected) Format(s fmt.State, verb rune) {
+	format(err, s, verb)
+}
@@ -0,0 +1,56 @@
+package xerrors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type validationError struct{}
+
+func (validationError) Error() string  { return "invalid" }
+func (validationError) Expected() bool { return true }
+
+func TestIsExpected(t *testing.T) {
+	for name, test := range map[string]struct {
+		err  error
+		want bool
+	}{
+		"nil":              {err: nil, want: false},
+		"unmarked":         {err: New("error"), want: false},
+		"expected":         {err: Expected(New("error")), want: true},
+		"joined":           {err: Join(Expected(New("error")), "its a wrap", io.EOF), want: true},
+		"joined_secondary": {err: Join(New("error"), Expected(io.EOF)), want: false},
+		"wrapped":          {err: fmt.Errorf("wrapped: %w", Expected(io.EOF)), want: true},
+		"outer_expected":   {err: Expected(Join(New("error"), "its a wrap")), want: true},
+		"canceled":         {err: context.Canceled, want: true},
+		"joined_canceled":  {err: Join(context.Canceled, "request aborted"), want: true},
+		"canceled_cause":   {err: Join(context.Canceled, "request aborted", io.EOF), want: false},
+		"expected_cause":   {err: Join(context.DeadlineExceeded, Expected(io.EOF)), want: true},
+		"deadline":         {err: context.DeadlineExceeded, want: false},
+		"custom":           {err: Join(validationError{}, "its a wrap"), want: true},
+		"std_join":         {err: errors.Join(io.EOF, Expected(io.EOF)), want: true},
+		"unexpected_mark":  {err: &expected{err: context.Canceled, expected: false}, want: false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.want, IsExpected(test.err))
+		})
+	}
+}
+
+func TestExpected(t *testing.T) {
+	err := Expected(New("user not found"))
+
+	assert.Equal(t, "user not found", err.Error())
+	assert.Contains(t, fmt.Sprintf("%+v", err), "expected: true")
+	assert.Equal(t, "*xerrors.errorString", Info(err).Type)
+	assert.Equal(t, ExpectedSeverity, Severity(err))
+	assert.Equal(t, slog.LevelWarn, Severity(WithSeverity(err, slog.LevelWarn)))
+	assert.Equal(t, ExpectedSeverity, Severity(context.Canceled))
+	assert.NoError(t, Expected(nil))
+}
@@ -0,0 +1,125 @@
+package xerrors
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"io/fs"
+	"reflect"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+)
+
+// fingerprintFrames is the number of in-module frames taken into account by Fingerprint.
+const fingerprintFrames = 3
+
+// sentinels holds the errors whose message is part of the fingerprint, see RegisterSentinel.
+var sentinels sync.Map
+
+func init() {
+	RegisterSentinel(io.EOF, io.ErrUnexpectedEOF, context.Canceled, context.DeadlineExceeded,
+		fs.ErrNotExist, fs.ErrExist, fs.ErrPermission, fs.ErrClosed)
+}
+
+// RegisterSentinel registers sentinel errors, such as the errors declared as package-level variables with errors.New,
+// so that their messages are part of the fingerprint of the errors holding them.
+// The errors created by New while initializing a package are registered automatically, and so are the sentinel
+// errors of the io, io/fs and context packages. Nil errors and errors of a non-comparable type are ignored.
+func RegisterSentinel(errs ...error) {
+	for _, err := range errs {
+		if err != nil && reflect.TypeOf(err).Comparable() {
+			sentinels.Store(err, struct{}{})
+		}
+	}
+}
+
+// modulePath is the path of the main module, empty if the binary has no build information.
+var modulePath = func() string {
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	return bi.Main.Path
+}()
+
+// Fingerprint returns a stable hash of err suitable to group the occurrences of the same error.
+// It is computed from the types of the errors of the chain, the messages of the sentinel errors, see RegisterSentinel,
+// and the functions of the top in-module frames of the stack trace. Variable data such as the messages given to Join
+// or built with fmt.Errorf, values and line numbers are ignored, so the fingerprint stays the same across builds
+// as long as the code producing the error does not change.
+func Fingerprint(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	errs := FlattenErrors(err)
+	var frames []Frame
+	for i := range errs {
+		if fs, ok := stackOf(errs[i]); ok {
+			frames = fs.Frames()
+			break
+		}
+	}
+	return fingerprint(errs, frames)
+}
+
+func fingerprint(errs []error, frames []Frame) string {
+	h := fnv.New64a()
+
+	for _, err := range errs {
+		if isSentinel(err) {
+			_, _ = fmt.Fprintf(h, "sentinel:%s\n", err.Error())
+		}
+		if isWrapper(err) {
+			continue
+		}
+		_, _ = fmt.Fprintf(h, "type:%T\n", err)
+	}
+
+	n := 0
+	for _, frame := range frames {
+		if n == fingerprintFrames {
+			break
+		}
+		if !inModule(frame.Function) {
+			continue
+		}
+		_, _ = fmt.Fprintf(h, "frame:%s\n", frame.Function)
+		n++
+	}
+
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// isSentinel reports whether err is a registered sentinel error.
+func isSentinel(err error) bool {
+	if !reflect.TypeOf(err).Comparable() {
+		return false
+	}
+	_, ok := sentinels.Load(err)
+	return ok
+}
+
+// initializing reports whether the first of callers is the initialization of a package,
+// a package-level variable or an init function.
+func initializing(callers Frames) bool {
+	if len(callers) == 0 {
+		return false
+	}
+	frame, _ := runtime.CallersFrames(callers).Next()
+	name := Frame{Function: frame.Function}.Name()
+	return name == "init" || strings.HasPrefix(name, "init.") && !strings.Contains(name, ".func")
+}
+
+// inModule reports whether function belongs to the main module.
+// When the main module is unknown, every function outside the standard library is considered in-module.
+func inModule(function string) bool {
+	if modulePath != "" {
+		return strings.HasPrefix(function, modulePath+".") || strings.HasPrefix(function, modulePath+"/")
+	}
+
+	return !Frame{Function: function}.StandardLibrary()
+}
@@ -0,0 +1,102 @@
+package xerrors
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func fingerprintSite(id int) error {
+	return Join(io.EOF, fmt.Sprintf("reading item %d", id), WithValue("id", id))
+}
+
+func fingerprintOtherSite(id int) error {
+	return Join(io.EOF, fmt.Sprintf("reading item %d", id), WithValue("id", id))
+}
+
+func TestFingerprint(t *testing.T) {
+	fp := Fingerprint(fingerprintSite(1))
+	assert.Len(t, fp, 16)
+
+	assert.Equal(t, fp, Fingerprint(fingerprintSite(2)), "variable data must be ignored")
+	assert.NotEqual(t, fp, Fingerprint(fingerprintOtherSite(1)), "the call site must be taken into account")
+	assert.Equal(t, fp, Info(fingerprintSite(3)).Fingerprint)
+}
+
+var (
+	errFingerprintA   = New("a")
+	errFingerprintB   = New("b")
+	errFingerprintStd = errors.New("std")
+)
+
+func TestFingerprintSentinel(t *testing.T) {
+	newErr := func(err error) error {
+		return Join(err, "wrap")
+	}
+	assert.NotEqual(t, Fingerprint(newErr(errFingerprintA)), Fingerprint(newErr(errFingerprintB)))
+	assert.Equal(t, Fingerprint(newErr(errFingerprintA)), Fingerprint(newErr(errFingerprintA)))
+	assert.NotEqual(t, Fingerprint(newErr(io.EOF)), Fingerprint(newErr(io.ErrUnexpectedEOF)))
+
+	assert.Equal(t, Fingerprint(newErr(errFingerprintStd)), Fingerprint(newErr(errors.New("other"))),
+		"the messages of the errors that are not registered are ignored")
+	RegisterSentinel(errFingerprintStd)
+	assert.NotEqual(t, Fingerprint(newErr(errFingerprintStd)), Fingerprint(newErr(errors.New("other"))))
+}
+
+func TestFingerprintVariableMessage(t *testing.T) {
+	for name, newErr := range map[string]func(id int) error{
+		"errorf": func(id int) error {
+			return Join(fmt.Errorf("user %d not found", id), "loading")
+		},
+		"new_sprintf": func(id int) error {
+			return New(fmt.Sprintf("user %d not found", id))
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, Fingerprint(newErr(1)), Fingerprint(newErr(2)))
+		})
+	}
+}
+
+type sliceError []string
+
+func (e sliceError) Error() string { return strings.Join(e, ", ") }
+
+func TestRegisterSentinel(t *testing.T) {
+	RegisterSentinel(nil, sliceError{"a"})
+
+	assert.True(t, isSentinel(errFingerprintA), "created while initializing the package")
+	assert.True(t, isSentinel(io.EOF))
+	assert.False(t, isSentinel(New("a")))
+	assert.False(t, isSentinel(sliceError{"a"}), "not comparable")
+}
+
+func TestFingerprintType(t *testing.T) {
+	newErr := func(err error) error {
+		return Join(err, "wrap")
+	}
+	assert.NotEqual(t,
+		Fingerprint(newErr(&net.ParseError{Type: "a"})),
+		Fingerprint(newErr(&net.AddrError{Err: "a"})),
+	)
+	assert.Equal(t,
+		Fingerprint(newErr(&net.ParseError{Type: "a"})),
+		Fingerprint(newErr(&net.ParseError{Type: "b"})),
+	)
+}
+
+func TestFingerprintNil(t *testing.T) {
+	assert.Empty(t, Fingerprint(nil))
+}
+
+func TestInModule(t *testing.T) {
+	assert.True(t, inModule("github.com/emilien-puget/xerrors.TestInModule"))
+	assert.True(t, inModule("github.com/emilien-puget/xerrors/xslog.(*Handler).Handle"))
+	assert.False(t, inModule("runtime.goexit"))
+	assert.False(t, inModule("github.com/stretchr/testify/assert.Equal"))
+}
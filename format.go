@@ -3,6 +3,7 @@ package xerrors
 import (
 	"fmt"
 	"io"
+	"strconv"
 	"sync"
 )
 
@@ -60,18 +61,40 @@ func stringify(err formattable) string {
 
 func format(err formattable, s fmt.State, verb rune) {
 	switch {
+	case verb == 'v' && s.Flag('#'):
+		_, _ = io.WriteString(s, goSyntax(err))
 	case verb == 'v' && s.Flag('+'):
-		werr := Unwrap(err)
-		if werr != nil {
-			_, _ = fmt.Fprintf(s, "%+v", werr)
-		}
-		msg := err.message(true)
-		if msg != "" {
-			_, _ = fmt.Fprintf(s, "%s", msg)
-		}
+		_, _ = fmt.Fprintf(s, directive(s, 's'), currentFormatter().Format(err, true))
+	case verb == 'v' && s.Flag(' '):
+		_, _ = fmt.Fprintf(s, directive(s, 's'), Logfmt(err))
 	case verb == 'v' || verb == 's':
-		_, _ = io.WriteString(s, err.Error())
+		_, _ = fmt.Fprintf(s, directive(s, 's'), currentFormatter().Format(err, false))
 	case verb == 'q':
-		_, _ = fmt.Fprintf(s, "%q", err.Error())
+		_, _ = fmt.Fprintf(s, directive(s, 'q'), currentFormatter().Format(err, false))
+	}
+}
+
+// directive returns the format directive for verb keeping the width, the precision and the relevant flags of s,
+// so that the width pads the rendering and the precision limits its length.
+func directive(s fmt.State, verb rune) string {
+	d := []byte{'%'}
+	if s.Flag('-') {
+		d = append(d, '-')
+	}
+	if verb == 'q' {
+		if s.Flag('+') {
+			d = append(d, '+')
+		}
+		if s.Flag('#') {
+			d = append(d, '#')
+		}
+	}
+	if width, ok := s.Width(); ok {
+		d = strconv.AppendInt(d, int64(width), 10)
+	}
+	if precision, ok := s.Precision(); ok {
+		d = append(d, '.')
+		d = strconv.AppendInt(d, int64(precision), 10)
 	}
+	return string(append(d, byte(verb)))
 }
@@ -21,13 +21,35 @@ func TestFormat(t *testing.T) {
 			expected: regexp.QuoteMeta(`error: its a wrap: its another wrap + with something more`),
 		},
 		{
-			ft:       "%+v",
-			expected: "^error\nstack\n\tgithub.com/emilien-puget/xerrors.TestFormat ([^ ]+)format_test.go:12\n\ttesting.tRunner ([^ ]+)testing.go:1595\n\truntime.goexit ([^ ]+)asm_amd64.s:1650\n: \tits a wrap: \tits another wrap\n\twith something more\n\tvalue: toto \"key1\"\n\tvalues: \\[toto: \"key1\" foo: \"404\"\\]$",
+			ft: "%+v",
+			expected: "^" + regexp.QuoteMeta("error: its a wrap: its another wrap + with something more\n"+
+				"├── error: its a wrap\n"+
+				"│   ├── error\n"+
+				"│   │   stack\n"+
+				"│   │     github.com/emilien-puget/xerrors.TestFormat ") + "[^\n]+format_test.go:12\n" +
+				"(│   │     [^\n]+\n)+" +
+				regexp.QuoteMeta("│   └── its a wrap\n"+
+					"├── its another wrap\n"+
+					"├── with something more\n"+
+					"├── value: toto \"key1\"\n") +
+				regexp.QuoteMeta(`└── values: [foo: "404" toto: "key1"]`) + "$",
 		},
 		{
 			ft:       "%s",
 			expected: regexp.QuoteMeta("error: its a wrap: its another wrap + with something more"),
 		},
+		{
+			ft:       "%.5v",
+			expected: "^error$",
+		},
+		{
+			ft:       "%-60s|",
+			expected: "^" + regexp.QuoteMeta("error: its a wrap: its another wrap + with something more   |") + "$",
+		},
+		{
+			ft:       "%#v",
+			expected: "^" + regexp.QuoteMeta("&xerrors.joinError{err:&xerrors.joinError{err:&xerrors.stack{err:&xerrors.errorString{s:\"error\"}, callers:xerrors.Frames{"),
+		},
 		{
 			ft:       "%q",
 			expected: regexp.QuoteMeta("\"error: its a wrap: its another wrap + with something more\""),
@@ -0,0 +1,222 @@
+package xerrors
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Formatter renders the errors of this package for the %v, %s, %q and %+v verbs of the [fmt] package.
+type Formatter interface {
+	// Format returns the rendering of err, verbose is set for the %+v verb.
+	Format(err error, verbose bool) string
+}
+
+// FormatterFunc is an adapter allowing the use of an ordinary function as a Formatter.
+type FormatterFunc func(err error, verbose bool) string
+
+// Format calls f(err, verbose).
+func (f FormatterFunc) Format(err error, verbose bool) string {
+	return f(err, verbose)
+}
+
+var (
+	formatterMu sync.RWMutex
+	formatter   Formatter = TreeFormatter{}
+)
+
+// SetFormatter sets the Formatter used by the fmt verbs, a nil Formatter restores the default TreeFormatter.
+func SetFormatter(f Formatter) {
+	if f == nil {
+		f = TreeFormatter{}
+	}
+	formatterMu.Lock()
+	defer formatterMu.Unlock()
+	formatter = f
+}
+
+func currentFormatter() Formatter {
+	formatterMu.RLock()
+	defer formatterMu.RUnlock()
+	return formatter
+}
+
+// FormatOptions configures Sprint.
+type FormatOptions struct {
+	// Formatter renders the error, nil uses the Formatter set by SetFormatter.
+	Formatter Formatter
+	// Verbose renders the error as the %+v verb would.
+	Verbose bool
+	// MaxLength is the maximum number of characters of the rendering, zero means no limit.
+	MaxLength int
+}
+
+// Sprint renders err using the options, it returns an empty string for a nil error.
+func Sprint(err error, opts FormatOptions) string {
+	if err == nil {
+		return ""
+	}
+	f := opts.Formatter
+	if f == nil {
+		f = currentFormatter()
+	}
+	return truncate(f.Format(err, opts.Verbose), opts.MaxLength)
+}
+
+// truncate returns the first n characters of s, n lower or equal to zero means no limit.
+func truncate(s string, n int) string {
+	if n <= 0 || utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	i := 0
+	for j := range s {
+		if i == n {
+			return s[:j]
+		}
+		i++
+	}
+	return s
+}
+
+// TreeFormatter is the default Formatter, it renders the message of the error and its tree when verbose.
+type TreeFormatter struct {
+	Options TreeOptions
+}
+
+// Format implements Formatter.
+func (f TreeFormatter) Format(err error, verbose bool) string {
+	if !verbose {
+		return err.Error()
+	}
+	return f.Options.Tree(err)
+}
+
+// CompactFormatter renders errors on a single line,
+// when verbose the values and the location of the error are appended to the message.
+type CompactFormatter struct{}
+
+// Format implements Formatter.
+func (CompactFormatter) Format(err error, verbose bool) string {
+	if !verbose {
+		return err.Error()
+	}
+
+	info := Info(err)
+
+	builder := bufferPool.Get().(*strings.Builder)
+	builder.Reset()
+	defer bufferPool.Put(builder)
+
+	builder.WriteString(err.Error())
+	if len(info.OrderedValues) > 0 {
+		builder.WriteString(" [")
+		for i, attr := range info.OrderedValues {
+			if i > 0 {
+				builder.WriteByte(' ')
+			}
+			_, _ = fmt.Fprintf(builder, "%s=%q", attr.Key, fmt.Sprint(attr.Value.Any()))
+		}
+		builder.WriteByte(']')
+	}
+	if len(info.Frames) > 0 {
+		frame := info.Frames[0]
+		_, _ = fmt.Fprintf(builder, " at %s %s:%d", frame.Function, filepath.Base(frame.File), frame.Line)
+	}
+
+	return builder.String()
+}
+
+// LogfmtFormatter renders errors as logfmt key=value pairs on a single line,
+// when verbose the type, the fingerprint, the values and the location of the error are added to the message.
+type LogfmtFormatter struct{}
+
+// Format implements Formatter.
+func (LogfmtFormatter) Format(err error, verbose bool) string {
+	return logfmt(err, verbose)
+}
+
+// JSONFormatter renders errors as a JSON object,
+// when verbose the type, the fingerprint, the values and the stack trace are added to the message.
+type JSONFormatter struct{}
+
+type jsonError struct {
+	Message     string         `json:"message"`
+	Type        string         `json:"type,omitempty"`
+	Fingerprint string         `json:"fingerprint,omitempty"`
+	Values      map[string]any `json:"values,omitempty"`
+	Stacktrace  []Frame        `json:"stacktrace,omitempty"`
+}
+
+// Format implements Formatter.
+func (JSONFormatter) Format(err error, verbose bool) string {
+	e := jsonError{Message: err.Error()}
+	if verbose {
+		info := Info(err)
+		e.Type = info.Type
+		e.Fingerprint = info.Fingerprint
+		e.Values = info.Values
+		e.Stacktrace = info.Frames
+	}
+
+	b, mErr := json.Marshal(e)
+	if mErr != nil {
+		// a value can't be encoded, fall back to the string representation of every value.
+		values := make(map[string]any, len(e.Values))
+		for k, v := range e.Values {
+			values[k] = fmt.Sprint(v)
+		}
+		e.Values = values
+		b, _ = json.Marshal(e)
+	}
+
+	return string(b)
+}
+
+// goSyntax returns a Go-syntax representation of the error chain, used by the %#v verb.
+func goSyntax(err error) string {
+	switch e := err.(type) {
+	case nil:
+		return "<nil>"
+	case *joinError:
+		errs := make([]string, 0, len(e.errs))
+		for _, err := range e.errs {
+			errs = append(errs, goSyntax(err))
+		}
+		return fmt.Sprintf("&xerrors.joinError{err:%s, errs:[]error{%s}}", goSyntax(e.err), strings.Join(errs, ", "))
+	case *stack:
+		return fmt.Sprintf("&xerrors.stack{err:%s, callers:%#v}", goSyntax(e.err), e.callers)
+	case *value:
+		return fmt.Sprintf("&xerrors.value{key:%q, value:%#v}", e.key, e.value)
+	case *multiValue:
+		return fmt.Sprintf("&xerrors.multiValue{values:%#v, keys:%#v}", e.values, e.keys)
+	case *attrValues:
+		return fmt.Sprintf("&xerrors.attrValues{attrs:[]slog.Attr{%s}}", goSyntaxAttrs(e.attrs))
+	case *publicMessage:
+		return fmt.Sprintf("&xerrors.publicMessage{err:%s, msg:%q}", goSyntax(e.err), e.msg)
+	case *withMessage:
+		return fmt.Sprintf("&xerrors.withMessage{err:%s, msg:%q}", goSyntax(e.err), e.msg)
+	case *severity:
+		return fmt.Sprintf("&xerrors.severity{err:%s, level:%d}", goSyntax(e.err), e.level)
+	case *expected:
+		return fmt.Sprintf("&xerrors.expected{err:%s, expected:%t}", goSyntax(e.err), e.expected)
+	case *localized:
+		return fmt.Sprintf("&xerrors.localized{id:%q, args:%#v}", e.id, e.args)
+	}
+	return fmt.Sprintf("%#v", err)
+}
+
+func goSyntaxAttrs(attrs []slog.Attr) string {
+	s := make([]string, 0, len(attrs))
+	for _, a := range attrs {
+		if a.Value.Kind() == slog.KindGroup {
+			s = append(s, fmt.Sprintf("slog.Group(%q, %s)", a.Key, goSyntaxAttrs(a.Value.Group())))
+			continue
+		}
+		s = append(s, fmt.Sprintf("slog.Any(%q, %#v)", a.Key, a.Value.Any()))
+	}
+	return strings.Join(s, ", ")
+}
@@ -0,0 +1,111 @@
+package xerrors
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatters(t *testing.T) {
+	err := Join(New("error"), "its a wrap", WithValues(map[string]any{"user": "bob smith", "status": 404}))
+
+	for name, test := range map[string]struct {
+		formatter Formatter
+		verbose   bool
+		expected  string
+	}{
+		"tree": {
+			formatter: TreeFormatter{},
+			expected:  "^error: its a wrap$",
+		},
+		"tree_verbose": {
+			formatter: TreeFormatter{Options: TreeOptions{ASCII: true}},
+			verbose:   true,
+			expected:  "^error: its a wrap\n\\|-- error\n\\|   stack\n",
+		},
+		"compact": {
+			formatter: CompactFormatter{},
+			expected:  "^error: its a wrap$",
+		},
+		"compact_verbose": {
+			formatter: CompactFormatter{},
+			verbose:   true,
+			expected:  `^error: its a wrap \[status="404" user="bob smith"\] at github.com/emilien-puget/xerrors.TestFormatters formatter_test.go:15$`,
+		},
+		"logfmt": {
+			formatter: LogfmtFormatter{},
+			expected:  `^msg="error: its a wrap"$`,
+		},
+		"logfmt_verbose": {
+			formatter: LogfmtFormatter{},
+			verbose:   true,
+			expected:  `^msg="error: its a wrap" type=\*xerrors.errorString fingerprint=[0-9a-f]{16} status=404 user="bob smith" at=xerrors.TestFormatters:formatter_test.go:15$`,
+		},
+		"json": {
+			formatter: JSONFormatter{},
+			expected:  `^{"message":"error: its a wrap"}$`,
+		},
+		"json_verbose": {
+			formatter: JSONFormatter{},
+			verbose:   true,
+			expected:  `^{"message":"error: its a wrap","type":"\*xerrors.errorString","fingerprint":"[0-9a-f]{16}","values":{"status":404,"user":"bob smith"},"stacktrace":\[{"file":"[^"]+formatter_test.go","line":15,"function":"github.com/emilien-puget/xerrors.TestFormatters"}`,
+		},
+		"func": {
+			formatter: FormatterFunc(func(err error, verbose bool) string { return fmt.Sprint("custom ", verbose) }),
+			verbose:   true,
+			expected:  "^custom true$",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Regexp(t, test.expected, Sprint(err, FormatOptions{Formatter: test.formatter, Verbose: test.verbose}))
+		})
+	}
+}
+
+func TestJSONFormatterUnsupportedValue(t *testing.T) {
+	err := Join(New("error"), WithValue("ch", make(chan int)))
+
+	var e map[string]any
+	require.NoError(t, json.Unmarshal([]byte(JSONFormatter{}.Format(err, true)), &e))
+	assert.IsType(t, "", e["values"].(map[string]any)["ch"])
+}
+
+func TestSetFormatter(t *testing.T) {
+	t.Cleanup(func() { SetFormatter(nil) })
+	err := Join(New("error"), "its a wrap")
+
+	SetFormatter(LogfmtFormatter{})
+	assert.Equal(t, `msg="error: its a wrap"`, fmt.Sprintf("%v", err))
+	assert.True(t, strings.HasPrefix(fmt.Sprintf("%+v", err), `msg="error: its a wrap" type=`))
+	assert.Equal(t, `msg="error: its a wrap"`, Sprint(err, FormatOptions{}))
+	assert.Equal(t, "error: its a wrap", err.Error())
+
+	SetFormatter(nil)
+	assert.Equal(t, "error: its a wrap", fmt.Sprintf("%v", err))
+}
+
+func TestSprint(t *testing.T) {
+	err := New("héllo world")
+
+	assert.Equal(t, "", Sprint(nil, FormatOptions{}))
+	assert.Equal(t, "héllo world", Sprint(err, FormatOptions{}))
+	assert.Equal(t, "héllo", Sprint(err, FormatOptions{MaxLength: 5}))
+	assert.Equal(t, "héllo world", Sprint(err, FormatOptions{MaxLength: 50}))
+}
+
+func TestGoSyntax(t *testing.T) {
+	err := WithPublicMessage(&joinError{
+		err:  &stack{err: newErrorString("error"), callers: Frames{1, 2}},
+		errs: []error{WithValue("key", 42), WithValues(map[string]any{"b": 2, "a": "1"}), errors.New("std")},
+	}, "oops")
+
+	assert.Equal(t,
+		`&xerrors.publicMessage{err:&xerrors.joinError{err:&xerrors.stack{err:&xerrors.errorString{s:"error"}, callers:xerrors.Frames{0x1, 0x2}}, `+
+			`errs:[]error{&xerrors.value{key:"key", value:42}, &xerrors.multiValue{values:map[string]interface {}{"a":"1", "b":2}, keys:[]string(nil)}, &errors.errorString{s:"std"}}}, msg:"oops"}`,
+		fmt.Sprintf("%#v", err))
+}
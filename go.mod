@@ -1,8 +1,11 @@
 module github.com/emilien-puget/xerrors
 
-go 1.21
+go 1.22
 
-require github.com/stretchr/testify v1.8.4
+require (
+	github.com/pkg/errors v0.9.1
+	github.com/stretchr/testify v1.9.0
+)
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
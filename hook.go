@@ -0,0 +1,92 @@
+package xerrors
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// CreationKind identifies the way an error was created.
+type CreationKind int
+
+const (
+	// CreatedByNew is the kind of the errors created by New, NewCtx and NewLocalized.
+	CreatedByNew CreationKind = iota
+	// CreatedByJoin is the kind of the errors created by Join and JoinCtx.
+	CreatedByJoin
+	// CreatedByValue is the kind of the errors created by WithValue, WithValues, WithOrderedValues and WithAttrs.
+	CreatedByValue
+	// CreatedByStack is the kind of the errors wrapping another error with the stack trace captured when it was created.
+	// They are created by the other constructors, so they are only passed to the hooks registered with OnCreateKind.
+	CreatedByStack
+	// CreatedByWrap is the kind of the errors created by WithPublicMessage, WithSeverity, Expected, WithMessage,
+	// Wrap and WithStack.
+	CreatedByWrap
+)
+
+type hook struct {
+	fn   func(err error)
+	kind CreationKind
+	all  bool
+}
+
+var (
+	// hooksMu serializes the registrations, the hooks themselves are read without locking.
+	hooksMu     sync.Mutex
+	createHooks atomic.Pointer[[]hook]
+	reportHooks atomic.Pointer[[]hook]
+)
+
+// OnCreate registers a hook called once with every error returned by a constructor of this package,
+// whatever its CreationKind. The stack traces captured by the constructors are not passed to it, see CreatedByStack.
+func OnCreate(fn func(err error)) {
+	addHook(&createHooks, hook{fn: fn, all: true})
+}
+
+// OnCreateKind registers a hook called with every error of the given kind created by this package.
+func OnCreateKind(kind CreationKind, fn func(err error)) {
+	addHook(&createHooks, hook{fn: fn, kind: kind})
+}
+
+// OnReport registers a hook called with every error passed to Report.
+func OnReport(fn func(err error)) {
+	addHook(&reportHooks, hook{fn: fn, all: true})
+}
+
+// Report calls the hooks registered with OnReport with err, it does nothing if err is nil.
+// It is meant to be called where an error is handled, for instance before responding to a request.
+func Report(err error) {
+	if err == nil {
+		return
+	}
+	runHooks(&reportHooks, 0, err)
+}
+
+// addHook registers h in hooks, the registered hooks are copied so that they can be read concurrently.
+func addHook(hooks *atomic.Pointer[[]hook], h hook) {
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+
+	var registered []hook
+	if old := hooks.Load(); old != nil {
+		registered = make([]hook, len(*old), len(*old)+1)
+		copy(registered, *old)
+	}
+	registered = append(registered, h)
+	hooks.Store(&registered)
+}
+
+func created(kind CreationKind, err error) {
+	runHooks(&createHooks, kind, err)
+}
+
+func runHooks(hooks *atomic.Pointer[[]hook], kind CreationKind, err error) {
+	registered := hooks.Load()
+	if registered == nil {
+		return
+	}
+	for _, h := range *registered {
+		if h.kind == kind || h.all && kind != CreatedByStack {
+			h.fn(err)
+		}
+	}
+}
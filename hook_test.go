@@ -0,0 +1,148 @@
+package xerrors
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func resetHooks(t *testing.T) {
+	t.Helper()
+	savedCreate, savedReport := createHooks.Swap(nil), reportHooks.Swap(nil)
+	t.Cleanup(func() {
+		createHooks.Store(savedCreate)
+		reportHooks.Store(savedReport)
+	})
+}
+
+func TestOnCreate(t *testing.T) {
+	resetHooks(t)
+	registerTestExtractor(t, requestIDExtractor)
+	ctx := context.WithValue(context.Background(), ctxKey("request_id"), "42")
+	var got []string
+	OnCreate(func(err error) {
+		got = append(got, fmt.Sprintf("%T", err))
+	})
+
+	_ = New("error")
+	_ = Join(errmy, "its a wrap")
+	_ = WithValue("key", "value")
+	_ = WithValues(map[string]any{"key": "value"})
+	_ = WithOrderedValues("key", "value")
+	_ = WithAttrs(slog.String("key", "value"))
+	_ = WithPublicMessage(errmy, "public")
+	_ = WithSeverity(errmy, slog.LevelWarn)
+	_ = Expected(errmy)
+	_ = Join(errmy, map[string]any{"key": "value"}, slog.Int("status", 500))
+	_ = NewCtx(ctx, "error")
+	_ = JoinCtx(ctx, errmy, "its a wrap")
+
+	assert.Equal(t, []string{
+		"*xerrors.stack",
+		"*xerrors.joinError",
+		"*xerrors.value",
+		"*xerrors.multiValue",
+		"*xerrors.multiValue",
+		"*xerrors.attrValues",
+		"*xerrors.publicMessage",
+		"*xerrors.severity",
+		"*xerrors.expected",
+		"*xerrors.joinError",
+		"*xerrors.joinError",
+		"*xerrors.joinError",
+	}, got)
+}
+
+func TestOnCreateKind(t *testing.T) {
+	resetHooks(t)
+	got := make(map[CreationKind][]error)
+	for _, kind := range []CreationKind{CreatedByNew, CreatedByJoin, CreatedByValue, CreatedByWrap} {
+		kind := kind
+		OnCreateKind(kind, func(err error) {
+			got[kind] = append(got[kind], err)
+		})
+	}
+
+	err1 := New("error")
+	err2 := Join(err1, "its a wrap")
+	err3 := NewCtx(context.Background(), "error")
+	err4 := JoinCtx(context.Background(), err3, "its a wrap")
+	err5 := WithValue("key", "value")
+	err6 := WithValues(map[string]any{"key": "value"})
+
+	err7 := WithPublicMessage(err1, "public")
+	err8 := Expected(err1)
+
+	assert.Equal(t, map[CreationKind][]error{
+		CreatedByNew:   {err1, err3},
+		CreatedByJoin:  {err2, err4},
+		CreatedByValue: {err5, err6},
+		CreatedByWrap:  {err7, err8},
+	}, got)
+}
+
+func TestOnCreateStack(t *testing.T) {
+	resetHooks(t)
+	var got []error
+	OnCreateKind(CreatedByStack, func(err error) {
+		got = append(got, err)
+	})
+
+	err := New("error")
+	_ = Join(err, "already has a stack")
+
+	assert.Equal(t, []error{err}, got)
+}
+
+func TestCreatedNoAlloc(t *testing.T) {
+	resetHooks(t)
+	err := newErrorString("error")
+
+	allocs := testing.AllocsPerRun(100, func() {
+		created(CreatedByNew, err)
+	})
+	assert.Zero(t, allocs)
+}
+
+func TestOnCreateConcurrent(t *testing.T) {
+	resetHooks(t)
+	var mu sync.Mutex
+	count := 0
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			OnCreate(func(err error) {
+				mu.Lock()
+				count++
+				mu.Unlock()
+			})
+			_ = New("error")
+		}()
+	}
+	wg.Wait()
+
+	count = 0
+	_ = WithValue("key", "value")
+	assert.Equal(t, 10, count)
+}
+
+func TestReport(t *testing.T) {
+	resetHooks(t)
+	var got []error
+	OnReport(func(err error) {
+		got = append(got, err)
+	})
+
+	err := New("error")
+	Report(err)
+	Report(nil)
+
+	assert.Equal(t, []error{err}, got)
+}
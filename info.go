@@ -1,56 +1,99 @@
 package xerrors
 
+import (
+	"fmt"
+	"log/slog"
+)
+
 // ErrorInfo contains information about the error chain, stack traces, and values associated with an error.
 type ErrorInfo struct {
 	ErrorChain  string
 	StackTraces []string
-	Values      map[string]any
-	Type        string
+	// Frames holds the frames of the stack trace, StackTraces holds their string representation.
+	Frames []Frame
+	Values map[string]any
+	// OrderedValues holds the values of Values in a deterministic order: the order of the errors of the chain,
+	// then the insertion order of WithOrderedValues and WithAttrs or the sorted keys of the other multiple values.
+	// The attributes given to WithAttrs keep their kinds and groups.
+	OrderedValues []slog.Attr
+	// Type is the Go type of the first error of the chain that is not a wrapper of this package.
+	Type string
+	// Fingerprint is the stable hash of the error returned by Fingerprint.
+	Fingerprint string
 }
 
 // Info returns information about the error chain, stack traces, and values.
 func Info(err error) ErrorInfo {
 	values := make(map[string]any)
+	var orderedValues []slog.Attr
+	positions := make(map[string]int)
 	var stackTraces []string
-	var errS *stack
-
-	typeString := ""
+	var frames []Frame
+	var callers Frames
 
 	errors := FlattenErrors(err)
 	for i := range errors {
-		switch et := errors[i].(type) {
-		case Valuer:
-			key, v := et.Value()
+		errorValues(errors[i], func(key string, v any) {
 			values[key] = v
-		case MultiValuer:
-			for s, a := range et.Value() {
-				values[s] = a
-			}
-		case *stack:
-			if errS == nil {
-				errS = et
+		})
+		for _, a := range errorAttrs(errors[i]) {
+			if pos, ok := positions[a.Key]; ok {
+				orderedValues[pos] = a
+				continue
 			}
+			positions[a.Key] = len(orderedValues)
+			orderedValues = append(orderedValues, a)
+		}
+		if callers == nil {
+			callers, _ = stackOf(errors[i])
 		}
 	}
 
 	s := ""
-	if fm, ok := err.(formattable); ok {
-		s = stringify(fm)
+	if err != nil {
+		s = err.Error()
 	}
 
-	if errS != nil {
-		fs := errS.StackFrames()
-		for _, frame := range fs.Frames() {
+	if callers != nil {
+		frames = callers.Frames()
+		for _, frame := range frames {
 			stackTraces = append(stackTraces, frame.String())
 		}
 	}
 
 	return ErrorInfo{
-		ErrorChain:  s,
-		StackTraces: stackTraces,
-		Values:      values,
-		Type:        typeString,
+		ErrorChain:    s,
+		StackTraces:   stackTraces,
+		Frames:        frames,
+		Values:        values,
+		OrderedValues: orderedValues,
+		Type:          typeOf(errors),
+		Fingerprint:   fingerprint(errors, frames),
+	}
+}
+
+// Type returns the Go type of the first error of the chain of err that is not a wrapper of this package,
+// as ErrorInfo.Type, without computing the rest of the information returned by Info.
+func Type(err error) string {
+	return typeOf(FlattenErrors(err))
+}
+
+func typeOf(errs []error) string {
+	for _, err := range errs {
+		if err != nil && !isWrapper(err) {
+			return fmt.Sprintf("%T", err)
+		}
+	}
+	return ""
+}
+
+// isWrapper reports whether err is one of the error types of this package that only decorate another error.
+func isWrapper(err error) bool {
+	switch err.(type) {
+	case *stack, *joinError, *value, *multiValue, *attrValues, *publicMessage, *withMessage, *severity, *expected:
+		return true
 	}
+	return false
 }
 
 // FlattenErrors recursively flattens nested errors into a slice of individual errors.
@@ -1,6 +1,8 @@
 package xerrors
 
 import (
+	"errors"
+	"net"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -15,6 +17,27 @@ func TestInfo(t *testing.T) {
 	assert.Equal(t, "plouf: its a wrap: An error occurred", info.ErrorChain)
 	assert.Equal(t, map[string]any{"key1": "value1", "key2": "value2"}, info.Values)
 	assert.Len(t, info.StackTraces, 3)
+	assert.Equal(t, "*xerrors.errorString", info.Type)
+}
+
+func TestInfoTypeForeign(t *testing.T) {
+	err := Join(&net.ParseError{Type: "_type", Text: "_text"}, "its a wrap")
+
+	info := Info(err)
+	assert.Equal(t, "*net.ParseError", info.Type)
+	assert.Equal(t, info.Type, Type(err))
+}
+
+func TestType(t *testing.T) {
+	assert.Equal(t, "*xerrors.errorString", Type(New("error")))
+	assert.Equal(t, "*errors.errorString", Type(errors.New("plouf")))
+	assert.Empty(t, Type(WithValue("key", "value")))
+	assert.Empty(t, Type(nil))
+}
+
+func TestInfoErrorChainForeign(t *testing.T) {
+	info := Info(errors.New("plouf"))
+	assert.Equal(t, "plouf", info.ErrorChain)
 }
 
 func createErrorGraph(depth int) error {
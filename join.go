@@ -21,13 +21,7 @@ type joinError struct {
 // LogValue implements the [slog.LogValuer] interface
 // it is our main point of entry to format the error as an attribute of a [slog.Record].
 func (err *joinError) LogValue() slog.Value {
-	info := Info(err)
-
-	return slog.GroupValue(
-		slog.String("message", info.ErrorChain),
-		slog.String("stacktrace", strings.Join(info.StackTraces, "\n")),
-		slog.Any("values", info.Values),
-	)
+	return logValue(err)
 }
 
 // Format implements the [fmt.Formatter] interface
@@ -36,29 +30,20 @@ func (err *joinError) Format(s fmt.State, verb rune) {
 	format(err, s, verb)
 }
 
-func (err *joinError) message(verbose bool) string {
-	if !verbose {
-		return err.fmt("%s", "%s", ": ", " + ")
-	}
-
-	return err.fmt("%+v", "\t%+v", ": ", "\n")
-}
-
-func (err *joinError) fmt(mainErrFmt, secErrFmt, mainErrSep, secErrSep string) string {
-	mainErrStr := fmt.Sprintf(mainErrFmt, err.err)
-
+// message ignores verbose, the verbose rendering of a joinError is the tree built by Tree.
+func (err *joinError) message(_ bool) string {
 	builder := bufferPool.Get().(*strings.Builder)
 	builder.Reset()
-	builder.WriteString(mainErrStr)
+	builder.WriteString(err.err.Error())
 
 	var hasErrors bool
 	for i := range err.errs {
-		s := fmt.Sprintf(secErrFmt, err.errs[i])
+		s := err.errs[i].Error()
 		if s != "" {
 			if hasErrors {
-				builder.WriteString(secErrSep)
+				builder.WriteString(" + ")
 			} else {
-				builder.WriteString(mainErrSep)
+				builder.WriteString(": ")
 			}
 			builder.WriteString(s)
 			hasErrors = true
@@ -76,6 +61,11 @@ func (err *joinError) Error() string {
 	return stringify(err)
 }
 
+// Cause implements the causer interface of github.com/pkg/errors, the cause of a joinError is its main error.
+func (err *joinError) Cause() error {
+	return err.err
+}
+
 func (err *joinError) Unwrap() []error {
 	n := 1 + len(err.errs) // Length of the resulting slice
 	i := make([]error, 0, n)
@@ -88,32 +78,122 @@ func (err *joinError) Unwrap() []error {
 
 // Join creates a new error that represents an error chain by joining the original error
 // with a list of additional errors.
+//
+// The additional errors can be given as:
+//   - an error,
+//   - a string or a [fmt.Stringer], used as a message,
+//   - a [slog.Attr], attached as a value like WithAttrs,
+//   - a map[string]any, attached as values like WithValues,
+//   - a []error, each of them being joined.
+//
+// Nil values are ignored, any other argument is joined as an "unsupported argument" error so that it is not lost.
+//
+// When ogErr is nil, the first additional error given as an error or in a []error takes its place, or the first
+// message if there is no such error, or the first value if there is no message either.
+// Join returns nil if every argument is nil, so that errors can be accumulated starting from a nil error.
 func Join(ogErr error, errs ...any) error {
-	n := 0
-	for _, err := range errs {
-		switch err.(type) {
-		case string:
-			n++
-		case error:
-			n++
-		}
+	e := join(3, ogErr, errs...)
+	if e == nil {
+		return nil
 	}
+	created(CreatedByJoin, e)
+	return e
+}
 
-	stackedErr := ensureStack(ogErr, 2)
+// join creates the joinError, skip is the number of frames to skip when capturing the stack.
+// It returns nil if ogErr and every additional error are nil.
+func join(skip int, ogErr error, errs ...any) *joinError {
 	e := &joinError{
-		err:  stackedErr,
-		errs: make([]error, 0, n),
+		err:  ogErr,
+		errs: make([]error, 0, len(errs)),
+	}
+	// promoted is the index in e.errs of the error taking the place of a nil ogErr, message the index of the first
+	// message taking its place if no error does.
+	promoted, message := -1, -1
+	promote := func(err error) {
+		if promoted < 0 && !isValue(err) {
+			promoted = len(e.errs)
+		}
+	}
+	addMessage := func(msg string) {
+		if message < 0 {
+			message = len(e.errs)
+		}
+		e.errs = append(e.errs, newErrorString(msg))
 	}
 
 	for i := 0; i < len(errs); i++ {
 		switch s := errs[i].(type) {
+		case nil:
 		case string:
-			e.errs = append(e.errs, newErrorString(s))
+			addMessage(s)
 		case error:
-			if s != nil {
-				e.errs = append(e.errs, s)
+			promote(s)
+			e.errs = append(e.errs, s)
+		case []error:
+			for _, err := range s {
+				if err != nil {
+					promote(err)
+					e.errs = append(e.errs, err)
+				}
 			}
+		case slog.Attr:
+			e.errs = append(e.errs, newAttrValues([]slog.Attr{s}))
+		case map[string]any:
+			e.errs = append(e.errs, newMultiValue(s))
+		case fmt.Stringer:
+			addMessage(s.String())
+		default:
+			addMessage(fmt.Sprintf("unsupported argument %T: %v", s, s))
 		}
 	}
+
+	if e.err == nil {
+		switch {
+		case len(e.errs) == 0:
+			return nil
+		case promoted < 0 && message >= 0:
+			promoted = message
+		case promoted < 0:
+			promoted = 0
+		}
+		e.err = e.errs[promoted]
+		e.errs = append(e.errs[:promoted], e.errs[promoted+1:]...)
+	}
+	e.err = ensureStack(e.err, skip)
 	return e
 }
+
+// isValue reports whether err only holds values, such as the errors created by WithValue.
+func isValue(err error) bool {
+	switch err.(type) {
+	case *value, *multiValue, *attrValues:
+		return true
+	}
+	return false
+}
+
+// JoinedErrors returns the errors joined with the main error of err, by Join or by errors.Join whose first error is
+// considered the main one, including the errors joined with the main error itself.
+// The messages and the values given to Join are left out, as they are part of the error joining them.
+func JoinedErrors(err error) []error {
+	for ; err != nil; err = Unwrap(err) {
+		switch u := err.(type) {
+		case *joinError:
+			joined := JoinedErrors(u.err)
+			for _, e := range u.errs {
+				if _, ok := e.(*errorString); !ok && !isValue(e) {
+					joined = append(joined, e)
+				}
+			}
+			return joined
+		case interface{ Unwrap() []error }:
+			errs := u.Unwrap()
+			if len(errs) == 0 {
+				return nil
+			}
+			return append(JoinedErrors(errs[0]), errs[1:]...)
+		}
+	}
+	return nil
+}
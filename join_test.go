@@ -2,10 +2,15 @@ package xerrors
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"log/slog"
+	"net"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -53,6 +58,26 @@ func TestJoinError_Error(t *testing.T) {
 			errs: []any{Join(err2, "sub")},
 			want: "err1: err2: sub",
 		},
+		"stringer": {
+			err:  err1,
+			errs: []any{net.IPv4(127, 0, 0, 1)},
+			want: "err1: 127.0.0.1",
+		},
+		"error_slice": {
+			err:  err1,
+			errs: []any{[]error{err2, nil, io.EOF}},
+			want: "err1: err2 + EOF",
+		},
+		"values": {
+			err:  err1,
+			errs: []any{slog.Int("status", 500), map[string]any{"foo": "bar"}, "a_string"},
+			want: "err1: a_string",
+		},
+		"unsupported": {
+			err:  err1,
+			errs: []any{42, "a_string"},
+			want: "err1: unsupported argument int: 42 + a_string",
+		},
 	} {
 		t.Run(name, func(t *testing.T) {
 			got := Join(test.err, test.errs...).Error()
@@ -61,6 +86,103 @@ func TestJoinError_Error(t *testing.T) {
 	}
 }
 
+func TestJoinValues(t *testing.T) {
+	err := Join(errmy, slog.Int("status", 500), slog.String("method", "GET"), map[string]any{"foo": "bar"})
+
+	assert.Equal(t, map[string]any{"status": int64(500), "method": "GET", "foo": "bar"}, Values(err))
+}
+
+func TestJoinNil(t *testing.T) {
+	err2 := errors.New("err2")
+	for name, test := range map[string]struct {
+		err         error
+		errs        []any
+		wantNil     bool
+		wantMessage string
+		wantErrs    int
+	}{
+		"no_args": {
+			wantNil: true,
+		},
+		"only_nils": {
+			errs:    []any{nil, nil},
+			wantNil: true,
+		},
+		"nil_error_slice": {
+			errs:    []any{[]error{nil, nil}},
+			wantNil: true,
+		},
+		"promote_error": {
+			errs:        []any{nil, err2},
+			wantMessage: "err2",
+		},
+		"only_string": {
+			errs:        []any{"a_string"},
+			wantMessage: "a_string",
+		},
+		"promote_string": {
+			errs:        []any{WithValue("key", "value"), "a_string", "other"},
+			wantMessage: "a_string: other",
+			wantErrs:    2,
+		},
+		"only_values": {
+			errs:     []any{WithValue("key", "value"), slog.Int("status", 500), map[string]any{"user": 42}},
+			wantErrs: 2,
+		},
+		"only_stringer": {
+			errs:        []any{time.Second},
+			wantMessage: "1s",
+		},
+		"promote_after_string": {
+			errs:        []any{"a_string", WithValue("key", "value"), err2},
+			wantMessage: "err2: a_string",
+			wantErrs:    2,
+		},
+		"promote_first": {
+			errs:        []any{nil, err2, "a_string"},
+			wantMessage: "err2: a_string",
+			wantErrs:    1,
+		},
+		"promote_from_slice": {
+			errs:        []any{[]error{nil, err2}, "a_string"},
+			wantMessage: "err2: a_string",
+			wantErrs:    1,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			newErr := Join(test.err, test.errs...)
+			if test.wantNil {
+				assert.NoError(t, newErr)
+				assert.NoError(t, JoinCtx(context.Background(), test.err, test.errs...))
+				return
+			}
+			require.Error(t, newErr)
+			assert.Equal(t, test.wantMessage, newErr.Error())
+			assert.Equal(t, test.wantMessage, fmt.Sprintf("%v", newErr))
+			assert.NotContains(t, fmt.Sprintf("%+v", newErr), "<nil>")
+
+			var joinErr *joinError
+			require.ErrorAs(t, newErr, &joinErr)
+			assert.NotNil(t, joinErr.err)
+			assert.Len(t, joinErr.errs, test.wantErrs)
+
+			info := Info(newErr)
+			assert.NotEmpty(t, info.StackTraces)
+			assert.Equal(t, "github.com/emilien-puget/xerrors.TestJoinNil.func1", info.Frames[0].Function)
+
+			logValue := newErr.(slog.LogValuer).LogValue()
+			assert.Equal(t, test.wantMessage, logValue.Group()[0].Value.String())
+		})
+	}
+}
+
+func TestJoinNilValues(t *testing.T) {
+	err := Join(nil, WithValue("key", "value"), map[string]any{"user": 42})
+
+	require.Error(t, err)
+	assert.Equal(t, map[string]any{"key": "value", "user": 42}, Values(err))
+}
+
 func TestJoinError_LogValueMethod(t *testing.T) {
 	err1 := New("err1")
 	err2 := New("err2")
@@ -144,10 +266,32 @@ runtime.goexit asm_amd64.s:1650`,
 			require.Contains(t, ms[0], "error")
 			require.IsType(t, map[string]any{}, ms[0]["error"])
 			a := ms[0]["error"].(map[string]any)
-			assert.Len(t, a, 3)
-			assert.NotEmpty(t, a["stacktrace"])
+			assert.Len(t, a, 5)
+			require.IsType(t, []any{}, a["stacktrace"])
+			assert.Len(t, a["stacktrace"], test.wantStackLength)
 			assert.Equal(t, test.wantMessage, a["message"])
 			assert.Equal(t, test.wantValues, a["values"])
 		})
 	}
 }
+
+func TestJoinedErrors(t *testing.T) {
+	secondary := New("secondary")
+	nested := Join(net.ErrClosed, io.ErrUnexpectedEOF)
+
+	for name, test := range map[string]struct {
+		err  error
+		want []error
+	}{
+		"nil":         {err: nil, want: nil},
+		"not_joined":  {err: New("error"), want: nil},
+		"join":        {err: Join(io.EOF, "its a wrap", secondary, WithValue("key", "value"), slog.Int("status", 500)), want: []error{secondary}},
+		"main_joined": {err: Join(nested, context.Canceled), want: []error{io.ErrUnexpectedEOF, context.Canceled}},
+		"wrapped":     {err: Expected(Join(io.EOF, secondary)), want: []error{secondary}},
+		"std_join":    {err: errors.Join(io.EOF, secondary, nested), want: []error{secondary, nested}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.want, JoinedErrors(test.err))
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package xerrors
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+// DefaultLanguage is the language of the messages returned by the Error method of localized errors,
+// and the last language tried by Localize.
+var DefaultLanguage = "en"
+
+// Catalog provides the templates of localized messages.
+type Catalog interface {
+	// Message returns the template of the message identified by id in lang, false if there is none.
+	Message(lang, id string) (string, bool)
+}
+
+// Localizable is an interface that allows custom error types to provide a message translated by a Catalog.
+type Localizable interface {
+	// MessageID returns the id of the message in the Catalog.
+	MessageID() string
+	// MessageArgs returns the arguments of the template of the message.
+	MessageArgs() map[string]any
+}
+
+var (
+	catalogMu sync.RWMutex
+	catalog   = &templateCatalog{Catalog: MapCatalog{}}
+)
+
+// SetCatalog sets the Catalog used by localized errors and Localize.
+// The templates of the catalog are parsed the first time they are rendered and cached until the next call to SetCatalog.
+func SetCatalog(c Catalog) {
+	catalogMu.Lock()
+	defer catalogMu.Unlock()
+	catalog = &templateCatalog{Catalog: c}
+}
+
+func currentCatalog() *templateCatalog {
+	catalogMu.RLock()
+	defer catalogMu.RUnlock()
+	return catalog
+}
+
+// templateCatalog caches the templates parsed from the messages of a Catalog.
+type templateCatalog struct {
+	Catalog
+	// templates holds the parsed templates by message text, nil if the text is not a valid template.
+	templates sync.Map
+}
+
+func (c *templateCatalog) template(text string) *template.Template {
+	if tmpl, ok := c.templates.Load(text); ok {
+		return tmpl.(*template.Template)
+	}
+	tmpl, err := template.New("message").Parse(text)
+	if err != nil {
+		tmpl = nil
+	}
+	c.templates.Store(text, tmpl)
+	return tmpl
+}
+
+// MapCatalog is a Catalog holding the templates by language then by message id.
+// Templates use the [text/template] syntax, the arguments of the message being the data, e.g. "user {{.name}} not found".
+type MapCatalog map[string]map[string]string
+
+// Message implements Catalog.
+func (c MapCatalog) Message(lang, id string) (string, bool) {
+	msg, ok := c[lang][id]
+	return msg, ok
+}
+
+// CatalogDecoder decodes the content of a catalog file into v, e.g. [json.Unmarshal] or yaml.Unmarshal.
+type CatalogDecoder func(data []byte, v any) error
+
+// CatalogOptions configures LoadCatalog.
+type CatalogOptions struct {
+	// Decoders are the decoders of the catalog files by file extension, e.g. ".yaml".
+	// JSON files, with the ".json" extension, are decoded with [json.Unmarshal] unless overridden.
+	Decoders map[string]CatalogDecoder
+}
+
+// LoadCatalog loads a MapCatalog from the files of fsys matching the patterns.
+// Each file holds the templates of one language, named after the file, by message id, e.g. "fr.json".
+// JSON files are supported, other formats such as YAML require a decoder in the options.
+func LoadCatalog(fsys fs.FS, opts *CatalogOptions, patterns ...string) (MapCatalog, error) {
+	decoders := map[string]CatalogDecoder{".json": json.Unmarshal}
+	if opts != nil {
+		for ext, dec := range opts.Decoders {
+			decoders[ext] = dec
+		}
+	}
+
+	c := make(MapCatalog)
+	for _, pattern := range patterns {
+		matches, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			return nil, Join(err, "matching catalog files", WithValue("pattern", pattern))
+		}
+		for _, name := range matches {
+			if err := c.load(fsys, name, decoders); err != nil {
+				return nil, Join(err, "loading catalog file", WithValue("file", name))
+			}
+		}
+	}
+	return c, nil
+}
+
+func (c MapCatalog) load(fsys fs.FS, name string, decoders map[string]CatalogDecoder) error {
+	ext := path.Ext(name)
+	decode, ok := decoders[ext]
+	if !ok {
+		return New("unsupported catalog file extension " + ext)
+	}
+
+	b, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return err
+	}
+	messages := make(map[string]string)
+	if err := decode(b, &messages); err != nil {
+		return err
+	}
+
+	lang := strings.TrimSuffix(path.Base(name), ext)
+	if c[lang] == nil {
+		c[lang] = make(map[string]string, len(messages))
+	}
+	for id, msg := range messages {
+		c[lang][id] = msg
+	}
+	return nil
+}
+
+// NewLocalized returns a new error with a stack whose message, identified by id, is rendered from the templates
+// of the Catalog with args. Error returns the message in DefaultLanguage, Localize in the requested language.
+func NewLocalized(id string, args map[string]any) error {
+	var err error = &localized{
+		id:   id,
+		args: args,
+	}
+	err = withStack(err, 2)
+	created(CreatedByNew, err)
+	return err
+}
+
+// Localize returns the outermost localizable message of the error chain rendered in lang.
+// The message is looked up in lang, then in its base language ("fr" for "fr-CA"), then in DefaultLanguage,
+// and falls back to its id. PublicMessage is returned if the chain has no localizable message.
+func Localize(err error, lang string) string {
+	errors := FlattenErrors(err)
+	for i := range errors {
+		if l, ok := errors[i].(Localizable); ok {
+			return currentCatalog().render(l.MessageID(), l.MessageArgs(), lang)
+		}
+	}
+	return PublicMessage(err)
+}
+
+func (c *templateCatalog) render(id string, args map[string]any, lang string) string {
+	langs := []string{lang}
+	if base, _, ok := strings.Cut(lang, "-"); ok {
+		langs = append(langs, base)
+	}
+	langs = append(langs, DefaultLanguage)
+
+	for _, l := range langs {
+		text, ok := c.Message(l, id)
+		if !ok {
+			continue
+		}
+		tmpl := c.template(text)
+		if tmpl == nil {
+			return text
+		}
+		var sb strings.Builder
+		if err := tmpl.Execute(&sb, args); err != nil {
+			return text
+		}
+		return sb.String()
+	}
+	return id
+}
+
+type localized struct {
+	id   string
+	args map[string]any
+}
+
+func (err *localized) MessageID() string {
+	return err.id
+}
+
+func (err *localized) MessageArgs() map[string]any {
+	return err.args
+}
+
+func (err *localized) Error() string {
+	return stringify(err)
+}
+
+func (err *localized) message(_ bool) string {
+	return currentCatalog().render(err.id, err.args, DefaultLanguage)
+}
+
+// LogValue implements the [slog.LogValuer] interface
+// it is our main point of entry to format the error as an attribute of a [slog.Record].
+func (err *localized) LogValue() slog.Value {
+	return logValue(err)
+}
+
+// Format implements the [fmt.Formatter] interface
+// it is our main point of entry to format the error using the [fmt] package.
+func (err *localized) Format(s fmt.State, verb rune) {
+	format(err, s, verb)
+}
@@ -0,0 +1,130 @@
+package xerrors
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setTestCatalog(t *testing.T, c Catalog) {
+	t.Helper()
+	saved := currentCatalog()
+	SetCatalog(c)
+	t.Cleanup(func() {
+		catalogMu.Lock()
+		defer catalogMu.Unlock()
+		catalog = saved
+	})
+}
+
+// lineDecoder decodes the "id: message" lines of a catalog file, standing for a YAML decoder.
+func lineDecoder(data []byte, v any) error {
+	messages := v.(*map[string]string)
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		id, msg, ok := strings.Cut(line, ": ")
+		if !ok {
+			return New("invalid line " + line)
+		}
+		(*messages)[id] = msg
+	}
+	return nil
+}
+
+func TestLoadCatalog(t *testing.T) {
+	fsys := fstest.MapFS{
+		"i18n/en.json":  {Data: []byte(`{"user_not_found": "user {{.name}} not found"}`)},
+		"i18n/fr.yaml":  {Data: []byte("user_not_found: utilisateur {{.name}} introuvable\n")},
+		"i18n/de.yml":   {Data: []byte("user_not_found: Benutzer {{.name}} nicht gefunden\n")},
+		"i18n/skip.txt": {Data: []byte("ignored")},
+	}
+
+	c, err := LoadCatalog(fsys, &CatalogOptions{
+		Decoders: map[string]CatalogDecoder{".yaml": lineDecoder, ".yml": lineDecoder},
+	}, "i18n/*.json", "i18n/*.yaml", "i18n/*.yml")
+	require.NoError(t, err)
+	assert.Equal(t, MapCatalog{
+		"en": {"user_not_found": "user {{.name}} not found"},
+		"fr": {"user_not_found": "utilisateur {{.name}} introuvable"},
+		"de": {"user_not_found": "Benutzer {{.name}} nicht gefunden"},
+	}, c)
+}
+
+func TestLoadCatalogError(t *testing.T) {
+	fsys := fstest.MapFS{
+		"en.json": {Data: []byte(`not json`)},
+		"en.txt":  {Data: []byte(`text`)},
+	}
+
+	_, err := LoadCatalog(fsys, nil, "*.json")
+	assert.ErrorContains(t, err, "loading catalog file")
+	_, err = LoadCatalog(fsys, nil, "*.txt")
+	assert.ErrorContains(t, err, "unsupported catalog file extension .txt")
+	_, err = LoadCatalog(fsys, nil, "[")
+	assert.ErrorContains(t, err, "matching catalog files")
+}
+
+func TestLocalize(t *testing.T) {
+	setTestCatalog(t, MapCatalog{
+		"en": {
+			"user_not_found": "user {{.name}} not found",
+			"only_english":   "only in english",
+		},
+		"fr": {"user_not_found": "utilisateur {{.name}} introuvable"},
+	})
+
+	err := NewLocalized("user_not_found", map[string]any{"name": "bob"})
+	err = Join(err, "loading user")
+
+	assert.Equal(t, "user bob not found: loading user", err.Error())
+	assert.Equal(t, "utilisateur bob introuvable", Localize(err, "fr"))
+	assert.Equal(t, "utilisateur bob introuvable", Localize(err, "fr-CA"))
+	assert.Equal(t, "user bob not found", Localize(err, "de"))
+	assert.Equal(t, "only in english", Localize(NewLocalized("only_english", nil), "fr"))
+	assert.Equal(t, "unknown_id", Localize(NewLocalized("unknown_id", nil), "fr"))
+	assert.Contains(t, fmt.Sprintf("%+v", err), "user bob not found")
+}
+
+func TestLocalizeTemplateCache(t *testing.T) {
+	setTestCatalog(t, MapCatalog{
+		"en": {"user_not_found": "user {{.name}} not found", "invalid": "invalid {{"},
+	})
+
+	for _, name := range []string{"bob", "alice"} {
+		assert.Equal(t, "user "+name+" not found", Localize(NewLocalized("user_not_found", map[string]any{"name": name}), "en"))
+		assert.Equal(t, "invalid {{", Localize(NewLocalized("invalid", nil), "en"))
+	}
+
+	parsed := 0
+	currentCatalog().templates.Range(func(_, _ any) bool {
+		parsed++
+		return true
+	})
+	assert.Equal(t, 2, parsed, "the templates must be parsed once")
+
+	SetCatalog(MapCatalog{"en": {"user_not_found": "no user {{.name}}"}})
+	assert.Equal(t, "no user bob", Localize(NewLocalized("user_not_found", map[string]any{"name": "bob"}), "en"))
+}
+
+func TestLocalizeOutermost(t *testing.T) {
+	setTestCatalog(t, MapCatalog{
+		"en": {"inner": "inner", "outer": "outer"},
+	})
+
+	err := Join(NewLocalized("outer", nil), NewLocalized("inner", nil))
+	assert.Equal(t, "outer", Localize(err, "en"))
+}
+
+func TestLocalizeFallbackPublicMessage(t *testing.T) {
+	err := WithPublicMessage(New("sql: no rows in result set"), "user not found")
+	assert.Equal(t, "user not found", Localize(err, "fr"))
+	assert.Equal(t, DefaultPublicMessage, Localize(New("error"), "fr"))
+}
+
+func TestNewLocalizedStack(t *testing.T) {
+	frames := StackFrames(NewLocalized("id", nil)).Frames()
+	assert.Equal(t, "github.com/emilien-puget/xerrors.TestNewLocalizedStack", frames[0].Function)
+}
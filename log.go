@@ -0,0 +1,31 @@
+package xerrors
+
+import (
+	"log/slog"
+)
+
+// logValue returns the group shared by every error type of this package when logged with [slog]:
+// the message, the type, the fingerprint, the stack trace as a list of frames and the values of the error as a group.
+// The stack trace of an expected error is empty, as with the handler of the xslog package.
+func logValue(err error) slog.Value {
+	info := Info(err)
+
+	frames := info.Frames
+	if frames == nil || IsExpected(err) {
+		frames = []Frame{}
+	}
+
+	// handlers drop empty groups, an empty map keeps the values in the output of an error without values.
+	values := slog.Any("values", map[string]any{})
+	if len(info.OrderedValues) > 0 {
+		values = slog.Attr{Key: "values", Value: slog.GroupValue(info.OrderedValues...)}
+	}
+
+	return slog.GroupValue(
+		slog.String("message", info.ErrorChain),
+		slog.String("type", info.Type),
+		slog.String("fingerprint", info.Fingerprint),
+		slog.Any("stacktrace", frames),
+		values,
+	)
+}
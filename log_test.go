@@ -0,0 +1,97 @@
+package xerrors
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogValue(t *testing.T) {
+	for name, test := range map[string]struct {
+		err       func() error
+		wantFirst string
+		wantJSON  string
+	}{
+		"new": {
+			err:       func() error { return New("error") },
+			wantFirst: "github.com/emilien-puget/xerrors.TestLogValue.func1 log_test.go:22",
+			wantJSON:  `{"message":"error","type":"*xerrors.errorString","values":{}}`,
+		},
+		"value": {
+			err:      func() error { return WithValue("key", "value") },
+			wantJSON: `{"message":"","type":"","values":{"key":"value"}}`,
+		},
+		"values": {
+			err:      func() error { return WithValues(map[string]any{"key": "value", "foo": 404}) },
+			wantJSON: `{"message":"","type":"","values":{"key":"value","foo":404}}`,
+		},
+		"join": {
+			err:       func() error { return Join(errors.New("plouf"), "its a wrap", WithValue("key", "value")) },
+			wantFirst: "github.com/emilien-puget/xerrors.TestLogValue.func4 log_test.go:35",
+			wantJSON:  `{"message":"plouf: its a wrap","type":"*errors.errorString","values":{"key":"value"}}`,
+		},
+		"expected": {
+			err:      func() error { return Expected(New("not found")) },
+			wantJSON: `{"message":"not found","type":"*xerrors.errorString","values":{}}`,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&buf, nil))
+			err := test.err()
+			logger.Info("test", slog.Any("error", err))
+
+			var m map[string]json.RawMessage
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &m))
+			var a map[string]json.RawMessage
+			require.NoError(t, json.Unmarshal(m["error"], &a))
+
+			var frames []Frame
+			require.NoError(t, json.Unmarshal(a["stacktrace"], &frames))
+			if test.wantFirst == "" {
+				assert.Empty(t, frames)
+			} else {
+				require.NotEmpty(t, frames)
+				first := frames[0]
+				first.File = filepath.Base(first.File)
+				assert.Equal(t, test.wantFirst, first.String())
+			}
+
+			var fingerprint string
+			require.NoError(t, json.Unmarshal(a["fingerprint"], &fingerprint))
+			assert.Equal(t, Fingerprint(err), fingerprint)
+
+			delete(a, "stacktrace")
+			delete(a, "fingerprint")
+			rest, mErr := json.Marshal(a)
+			require.NoError(t, mErr)
+			assert.JSONEq(t, test.wantJSON, string(rest))
+		})
+	}
+}
+
+func TestLogValueFrames(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	logger.Info("test", slog.Any("error", New("error")))
+
+	var m map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &m))
+	a := m["error"].(map[string]any)
+	stack := a["stacktrace"].([]any)
+	require.Len(t, stack, 3)
+	for _, f := range stack {
+		require.IsType(t, map[string]any{}, f)
+		assert.Len(t, f, 3)
+		assert.Contains(t, f, "function")
+		assert.Contains(t, f, "file")
+		assert.Contains(t, f, "line")
+	}
+	assert.Equal(t, "github.com/emilien-puget/xerrors.TestLogValueFrames", stack[0].(map[string]any)["function"])
+}
@@ -0,0 +1,96 @@
+package xerrors
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Logfmt renders err on a single line as logfmt key=value pairs: the message, the type, the fingerprint,
+// the values and the location of the error, e.g.
+//
+//	msg="user not found" type=*xerrors.errorString fingerprint=5d1f0e2c1b3a4f6e user=bob at=users.Get:users.go:42
+//
+// Values implementing [slog.LogValuer] are resolved, allowing them to redact themselves.
+// It is the rendering of the "% v" verb, it returns an empty string for a nil error.
+func Logfmt(err error) string {
+	if err == nil {
+		return ""
+	}
+	return logfmt(err, true)
+}
+
+// logfmt renders err as logfmt key=value pairs,
+// when verbose the type, the fingerprint, the values and the location of the error are added to the message.
+func logfmt(err error, verbose bool) string {
+	builder := bufferPool.Get().(*strings.Builder)
+	builder.Reset()
+	defer bufferPool.Put(builder)
+
+	writeLogfmt(builder, "msg", err.Error())
+	if !verbose {
+		return builder.String()
+	}
+
+	info := Info(err)
+	if info.Type != "" {
+		writeLogfmt(builder, "type", info.Type)
+	}
+	writeLogfmt(builder, "fingerprint", info.Fingerprint)
+	for _, attr := range info.OrderedValues {
+		writeLogfmt(builder, attr.Key, attr.Value.Resolve().String())
+	}
+	if len(info.Frames) > 0 {
+		frame := info.Frames[0]
+		writeLogfmt(builder, "at", shortFunction(frame.Function)+":"+filepath.Base(frame.File)+":"+strconv.Itoa(frame.Line))
+	}
+
+	return builder.String()
+}
+
+func writeLogfmt(builder *strings.Builder, key, value string) {
+	if builder.Len() > 0 {
+		builder.WriteByte(' ')
+	}
+	builder.WriteString(logfmtKey(key))
+	builder.WriteByte('=')
+	if needsQuoting(value) {
+		builder.WriteString(strconv.Quote(value))
+		return
+	}
+	builder.WriteString(value)
+}
+
+// logfmtKey replaces the characters that are not allowed in a logfmt key by an underscore.
+func logfmtKey(key string) string {
+	if key == "" {
+		return "_"
+	}
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r == '=' || r == '"' || !unicode.IsPrint(r) {
+			return '_'
+		}
+		return r
+	}, key)
+}
+
+func needsQuoting(value string) bool {
+	if value == "" {
+		return true
+	}
+	for _, r := range value {
+		if r <= ' ' || r == '=' || r == '"' || r == '\\' || !unicode.IsPrint(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// shortFunction strips the import path of a function name, github.com/org/pkg.Func becomes pkg.Func.
+func shortFunction(function string) string {
+	if i := strings.LastIndex(function, "/"); i >= 0 {
+		return function[i+1:]
+	}
+	return function
+}
@@ -0,0 +1,85 @@
+package xerrors
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type password string
+
+func (password) LogValue() slog.Value {
+	return slog.StringValue("REDACTED")
+}
+
+func TestLogfmt(t *testing.T) {
+	for name, test := range map[string]struct {
+		err      error
+		expected string
+	}{
+		"nil": {
+			err:      nil,
+			expected: "^$",
+		},
+		"std": {
+			err:      errors.New("boom"),
+			expected: "^msg=boom type=\\*errors.errorString fingerprint=[0-9a-f]{16}$",
+		},
+		"multi_line": {
+			err:      errors.Join(errors.New("a"), errors.New("b")),
+			expected: `^msg="a\\nb" type=\*errors.joinError fingerprint=[0-9a-f]{16}$`,
+		},
+		"values": {
+			err: Join(New("user not found"), WithValues(map[string]any{
+				"user":     "bob smith",
+				"attempts": 3,
+				"password": password("hunter2"),
+			})),
+			expected: `^msg="user not found" type=\*xerrors.errorString fingerprint=[0-9a-f]{16} attempts=3 password=REDACTED user="bob smith" at=xerrors.TestLogfmt:logfmt_test.go:37$`,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Regexp(t, test.expected, Logfmt(test.err))
+		})
+	}
+}
+
+func TestLogfmtVerb(t *testing.T) {
+	err := Join(New("error"), "its a wrap")
+
+	assert.Equal(t, Logfmt(err), fmt.Sprintf("% v", err))
+	assert.Equal(t, `msg="error`, fmt.Sprintf("% .10v", err))
+	assert.NotContains(t, fmt.Sprintf("% v", err), "\n")
+}
+
+func TestWriteLogfmt(t *testing.T) {
+	for name, test := range map[string]struct {
+		key      string
+		value    string
+		expected string
+	}{
+		"plain":     {key: "user", value: "bob", expected: "user=bob"},
+		"empty":     {key: "user", value: "", expected: `user=""`},
+		"space":     {key: "user", value: "bob smith", expected: `user="bob smith"`},
+		"quote":     {key: "user", value: `bob "the" smith`, expected: `user="bob \"the\" smith"`},
+		"equal":     {key: "user", value: "a=b", expected: `user="a=b"`},
+		"newline":   {key: "user", value: "a\nb", expected: `user="a\nb"`},
+		"key_space": {key: "user name", value: "bob", expected: "user_name=bob"},
+		"key_empty": {key: "", value: "bob", expected: "_=bob"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var builder strings.Builder
+			writeLogfmt(&builder, test.key, test.value)
+			assert.Equal(t, test.expected, builder.String())
+		})
+	}
+}
+
+func TestShortFunction(t *testing.T) {
+	assert.Equal(t, "xerrors.New", shortFunction("github.com/emilien-puget/xerrors.New"))
+	assert.Equal(t, "main.main", shortFunction("main.main"))
+}
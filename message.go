@@ -0,0 +1,64 @@
+package xerrors
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// WithMessage returns err with msg prepended to its message, "msg: err", as the errors.WithMessage function of
+// github.com/pkg/errors does. Contrary to Join, err stays the main error and no stack trace is captured.
+// WithMessage returns nil if err is nil.
+func WithMessage(err error, msg string) error {
+	if err == nil {
+		return nil
+	}
+	e := &withMessage{
+		err: err,
+		msg: msg,
+	}
+	created(CreatedByWrap, e)
+	return e
+}
+
+// Wrap returns err with msg prepended to its message and the stack trace of the caller,
+// as the errors.Wrap function of github.com/pkg/errors does. Wrap returns nil if err is nil.
+func Wrap(err error, msg string) error {
+	if err == nil {
+		return nil
+	}
+	e := withStack(&withMessage{
+		err: err,
+		msg: msg,
+	}, 2)
+	created(CreatedByWrap, e)
+	return e
+}
+
+type withMessage struct {
+	err error
+	msg string
+}
+
+func (err *withMessage) Unwrap() error {
+	return err.err
+}
+
+func (err *withMessage) Error() string {
+	return stringify(err)
+}
+
+func (err *withMessage) message(_ bool) string {
+	return err.msg
+}
+
+// LogValue implements the [slog.LogValuer] interface
+// it is our main point of entry to format the error as an attribute of a [slog.Record].
+func (err *withMessage) LogValue() slog.Value {
+	return logValue(err)
+}
+
+// Format implements the [fmt.Formatter] interface
+// it is our main point of entry to format the error using the [fmt] package.
+func (err *withMessage) Format(s fmt.State, verb rune) {
+	format(err, s, verb)
+}
@@ -0,0 +1,43 @@
+package xerrors
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithMessage(t *testing.T) {
+	err := WithMessage(io.EOF, "reading")
+
+	assert.Equal(t, "reading: EOF", err.Error())
+	assert.ErrorIs(t, err, io.EOF)
+	assert.Nil(t, StackFrames(err))
+	assert.Equal(t, "*errors.errorString", Info(err).Type)
+	assert.Equal(t, "reading: EOF", fmt.Sprintf("%v", err))
+	assert.NoError(t, WithMessage(nil, "reading"))
+}
+
+func TestWrap(t *testing.T) {
+	errSentinel := New("not found")
+	err := Wrap(errSentinel, "loading")
+
+	assert.Equal(t, "loading: not found", err.Error())
+	assert.ErrorIs(t, err, errSentinel)
+	frames := Info(err).Frames
+	assert.Equal(t, "github.com/emilien-puget/xerrors.TestWrap", frames[0].Function)
+	assert.Equal(t, 24, frames[0].Line, "the stack trace must be the one of the call to Wrap")
+	assert.NoError(t, Wrap(nil, "loading"))
+}
+
+func TestWithStack(t *testing.T) {
+	errSentinel := New("not found")
+	err := WithStack(errSentinel)
+
+	assert.Equal(t, "not found", err.Error())
+	assert.ErrorIs(t, err, errSentinel)
+	frames := Info(err).Frames
+	assert.Equal(t, 36, frames[0].Line, "the stack trace must be the one of the call to WithStack")
+	assert.NoError(t, WithStack(nil))
+}
@@ -2,6 +2,8 @@ package xerrors
 
 import (
 	"fmt"
+	"log/slog"
+	"sort"
 	"strings"
 )
 
@@ -13,21 +15,77 @@ type MultiValuer interface {
 	Value() map[string]any
 }
 
-// WithValues return an error that contains multiple values.
+// WithValues return an error that contains multiple values, rendered in the order of their sorted keys.
 func WithValues(v map[string]any) error {
+	err := newMultiValue(v)
+	created(CreatedByValue, err)
+	return err
+}
+
+// newMultiValue creates the error of WithValues without calling the hooks, for the constructors joining values.
+func newMultiValue(v map[string]any) *multiValue {
 	return &multiValue{
 		values: v,
 	}
 }
 
+// badKey is the key of a value given to WithOrderedValues without a key, as [slog] does.
+const badKey = "!BADKEY"
+
+// WithOrderedValues return an error that contains multiple values rendered in the order they are given.
+// The arguments are given like the ones of [slog.Logger.Info]: a [slog.Attr] or a string key followed by its value,
+// a value without a key being associated to the "!BADKEY" key.
+//
+//	err = xerrors.Join(err, xerrors.WithOrderedValues("user", id, slog.Int("status", 404)))
+func WithOrderedValues(args ...any) error {
+	err := &multiValue{
+		values: make(map[string]any, len(args)),
+		keys:   make([]string, 0, len(args)),
+	}
+	for len(args) > 0 {
+		var key string
+		var val any
+		switch a := args[0].(type) {
+		case slog.Attr:
+			key, val = a.Key, a.Value.Any()
+			args = args[1:]
+		case string:
+			if len(args) == 1 {
+				key, val = badKey, a
+				args = nil
+				break
+			}
+			key, val = a, args[1]
+			args = args[2:]
+		default:
+			key, val = badKey, a
+			args = args[1:]
+		}
+		if _, ok := err.values[key]; !ok {
+			err.keys = append(err.keys, key)
+		}
+		err.values[key] = val
+	}
+	created(CreatedByValue, err)
+	return err
+}
+
 type multiValue struct {
 	values map[string]any
+	// keys holds the keys of values in insertion order, nil when the values are rendered in the order of the sorted keys.
+	keys []string
 }
 
 func (err *multiValue) Value() map[string]any {
 	return err.values
 }
 
+// LogValue implements the [slog.LogValuer] interface
+// it is our main point of entry to format the error as an attribute of a [slog.Record].
+func (err *multiValue) LogValue() slog.Value {
+	return logValue(err)
+}
+
 // Format implements the [fmt.Formatter] interface
 // it is our main point of entry to format the error using the [fmt] package.
 func (err *multiValue) Format(s fmt.State, verb rune) {
@@ -44,9 +102,33 @@ func (err *multiValue) message(verbose bool) string {
 	}
 	keyValuePairs := make([]string, 0, len(err.values))
 
-	for key, v := range err.values {
-		keyValuePairs = append(keyValuePairs, fmt.Sprintf("%s: \"%v\"", key, v))
+	for _, key := range multiValueKeys(err) {
+		keyValuePairs = append(keyValuePairs, fmt.Sprintf("%s: \"%v\"", key, err.values[key]))
 	}
 
 	return "values: [" + strings.Join(keyValuePairs, " ") + "]"
 }
+
+func (err *multiValue) orderedKeys() []string {
+	return err.keys
+}
+
+// multiValueKeys returns the keys of the values of mv in the order they are rendered:
+// the insertion order for WithOrderedValues and WithAttrs, the sorted keys otherwise.
+func multiValueKeys(mv MultiValuer) []string {
+	if o, ok := mv.(interface{ orderedKeys() []string }); ok {
+		if keys := o.orderedKeys(); keys != nil {
+			return keys
+		}
+	}
+	return sortedKeys(mv.Value())
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -1,6 +1,8 @@
 package xerrors
 
 import (
+	"fmt"
+	"log/slog"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -28,3 +30,68 @@ func TestMultiValueOverWrite(t *testing.T) {
 	}
 	assert.Equal(t, expected, vals)
 }
+
+func TestMultiValueMessageOrder(t *testing.T) {
+	err := WithValues(map[string]any{"c": 3, "a": 1, "b": 2})
+	for range 10 {
+		assert.Equal(t, `values: [a: "1" b: "2" c: "3"]`, fmt.Sprintf("%+v", err))
+	}
+}
+
+func TestWithOrderedValues(t *testing.T) {
+	for name, test := range map[string]struct {
+		args     []any
+		expected string
+		values   map[string]any
+	}{
+		"pairs": {
+			args:     []any{"c", 3, "a", 1},
+			expected: `values: [c: "3" a: "1"]`,
+			values:   map[string]any{"c": 3, "a": 1},
+		},
+		"attrs": {
+			args:     []any{slog.Int("c", 3), "b", "two", slog.String("a", "one")},
+			expected: `values: [c: "3" b: "two" a: "one"]`,
+			values:   map[string]any{"c": int64(3), "b": "two", "a": "one"},
+		},
+		"duplicate": {
+			args:     []any{"a", 1, "b", 2, "a", 3},
+			expected: `values: [a: "3" b: "2"]`,
+			values:   map[string]any{"a": 3, "b": 2},
+		},
+		"missing_value": {
+			args:     []any{"a", 1, "b"},
+			expected: `values: [a: "1" !BADKEY: "b"]`,
+			values:   map[string]any{"a": 1, "!BADKEY": "b"},
+		},
+		"missing_key": {
+			args:     []any{42, "a", 1},
+			expected: `values: [!BADKEY: "42" a: "1"]`,
+			values:   map[string]any{"!BADKEY": 42, "a": 1},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := WithOrderedValues(test.args...)
+			assert.Equal(t, test.expected, fmt.Sprintf("%+v", err))
+			assert.Equal(t, test.values, Values(err))
+		})
+	}
+}
+
+func TestInfoOrderedValues(t *testing.T) {
+	err := Join(New("error"),
+		WithOrderedValues("z", 1, "y", 2),
+		WithValue("x", 3),
+		WithValues(map[string]any{"b": 4, "a": 5, "z": 6}),
+	)
+
+	info := Info(err)
+	assert.Equal(t, []slog.Attr{
+		slog.Int("z", 6),
+		slog.Int("y", 2),
+		slog.Int("x", 3),
+		slog.Int("a", 5),
+		slog.Int("b", 4),
+	}, info.OrderedValues)
+	assert.Equal(t, map[string]any{"z": 6, "y": 2, "x": 3, "a": 5, "b": 4}, info.Values)
+}
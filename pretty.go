@@ -0,0 +1,56 @@
+package xerrors
+
+import (
+	"os"
+)
+
+// ANSI SGR parameters of the colorized rendering.
+const (
+	styleMessage      = "1;31" // bold red
+	styleValue        = "36"   // cyan
+	styleModuleFrame  = "1"    // bold
+	styleLibraryFrame = "2"    // dim
+	styleDim          = "2"
+)
+
+// ColorEnabled reports whether the output to f should be colorized:
+// f is a terminal and the NO_COLOR environment variable is not set to a non-empty value, see https://no-color.org.
+func ColorEnabled(f *os.File) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	if f == nil {
+		return false
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
+
+// PrettyFormatter is a Formatter for development rendering the tree of the errors with ANSI colors:
+// the messages in bold red, the values highlighted, the frames of the main module emphasized and the others dimmed.
+//
+//	xerrors.SetFormatter(xerrors.PrettyFormatter{Color: xerrors.ColorEnabled(os.Stderr)})
+type PrettyFormatter struct {
+	Options TreeOptions
+	// Color enables the ANSI escape sequences, without it the rendering is the one of TreeFormatter.
+	Color bool
+}
+
+// Format implements Formatter.
+func (f PrettyFormatter) Format(err error, verbose bool) string {
+	if !verbose {
+		if !f.Color {
+			return err.Error()
+		}
+		return "\x1b[" + styleMessage + "m" + err.Error() + "\x1b[0m"
+	}
+	return f.Options.tree(err, f.Color)
+}
+
+// Pretty renders err as a tree, colorized when the standard error is a terminal and NO_COLOR is not set.
+func Pretty(err error) string {
+	return PrettyFormatter{Color: ColorEnabled(os.Stderr)}.Format(err, true)
+}
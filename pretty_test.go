@@ -0,0 +1,98 @@
+package xerrors
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrettyFormatter(t *testing.T) {
+	err := Join(New("error"), "its a wrap", WithValue("user", "bob"))
+
+	for name, test := range map[string]struct {
+		formatter PrettyFormatter
+		verbose   bool
+		expected  string
+	}{
+		"plain": {
+			formatter: PrettyFormatter{},
+			expected:  "^error: its a wrap$",
+		},
+		"plain_verbose": {
+			formatter: PrettyFormatter{},
+			verbose:   true,
+			expected:  "^" + regexp.QuoteMeta(Tree(err)) + "$",
+		},
+		"color": {
+			formatter: PrettyFormatter{Color: true},
+			expected:  "^" + regexp.QuoteMeta("\x1b[1;31merror: its a wrap\x1b[0m") + "$",
+		},
+		"color_verbose": {
+			formatter: PrettyFormatter{Color: true},
+			verbose:   true,
+			expected: "^" + regexp.QuoteMeta("\x1b[1;31merror: its a wrap\x1b[0m\n├── \x1b[1;31merror\x1b[0m\n│   stack\n"+
+				"│   \x1b[1m  github.com/emilien-puget/xerrors.TestPrettyFormatter ") + "[^\n]+pretty_test.go:15\x1b\\[0m\n" +
+				regexp.QuoteMeta("│   \x1b[2m  testing.tRunner ") + "[^\n]+\n" +
+				"(│   [^\n]+\n)*" +
+				regexp.QuoteMeta("├── \x1b[1;31mits a wrap\x1b[0m\n└── \x1b[36mvalue: user \"bob\"\x1b[0m") + "$",
+		},
+		"ascii": {
+			formatter: PrettyFormatter{Options: TreeOptions{ASCII: true, MaxChildren: 1}, Color: true},
+			verbose:   true,
+			expected:  regexp.QuoteMeta("`-- \x1b[2m... 2 more\x1b[0m") + "$",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Regexp(t, test.expected, test.formatter.Format(err, test.verbose))
+		})
+	}
+}
+
+func TestPrettyFormatterValues(t *testing.T) {
+	for name, test := range map[string]struct {
+		err      error
+		expected string
+	}{
+		"value": {
+			err:      WithValue("user", "bob"),
+			expected: "\x1b[36mvalue: user \"bob\"\x1b[0m",
+		},
+		"values": {
+			err:      WithValues(map[string]any{"user": "bob"}),
+			expected: "\x1b[36mvalues: [user: \"bob\"]\x1b[0m",
+		},
+		"attrs": {
+			err:      WithAttrs(slog.String("user", "bob")),
+			expected: "\x1b[36mvalues: [user: \"bob\"]\x1b[0m",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			out := PrettyFormatter{Color: true}.Format(Join(errmy, test.err), true)
+			assert.Contains(t, out, test.expected)
+		})
+	}
+}
+
+func TestColorEnabled(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "out"))
+	require.NoError(t, err)
+	defer f.Close()
+
+	assert.False(t, ColorEnabled(nil))
+	assert.False(t, ColorEnabled(f))
+
+	t.Setenv("NO_COLOR", "1")
+	assert.False(t, ColorEnabled(os.Stderr))
+}
+
+func TestPretty(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	err := Join(New("error"), "its a wrap")
+
+	assert.Equal(t, Tree(err), Pretty(err))
+}
@@ -0,0 +1,77 @@
+package xerrors
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// DefaultPublicMessage is the message returned by PublicMessage when an error has no public message.
+var DefaultPublicMessage = "an internal error occurred"
+
+// PublicMessager is an interface that allows custom error types to provide a message that is safe to show to the clients
+// of an API, as opposed to the message returned by Error, which is meant for logs.
+type PublicMessager interface {
+	// PublicMessage returns the message safe to show to clients.
+	PublicMessage() string
+}
+
+// WithPublicMessage returns err with a message that is safe to show to the clients of an API.
+// The message returned by Error is left untouched. WithPublicMessage returns nil if err is nil.
+func WithPublicMessage(err error, msg string) error {
+	if err == nil {
+		return nil
+	}
+	e := &publicMessage{
+		err: err,
+		msg: msg,
+	}
+	created(CreatedByWrap, e)
+	return e
+}
+
+// PublicMessage returns the outermost public message of the error chain, DefaultPublicMessage if there is none.
+func PublicMessage(err error) string {
+	errors := FlattenErrors(err)
+	for i := range errors {
+		if pm, ok := errors[i].(PublicMessager); ok {
+			return pm.PublicMessage()
+		}
+	}
+	return DefaultPublicMessage
+}
+
+type publicMessage struct {
+	err error
+	msg string
+}
+
+func (err *publicMessage) PublicMessage() string {
+	return err.msg
+}
+
+func (err *publicMessage) Unwrap() error {
+	return err.err
+}
+
+func (err *publicMessage) Error() string {
+	return stringify(err)
+}
+
+func (err *publicMessage) message(verbose bool) string {
+	if !verbose {
+		return ""
+	}
+	return fmt.Sprintf("\npublic message: %q", err.msg)
+}
+
+// LogValue implements the [slog.LogValuer] interface
+// it is our main point of entry to format the error as an attribute of a [slog.Record].
+func (err *publicMessage) LogValue() slog.Value {
+	return logValue(err)
+}
+
+// Format implements the [fmt.Formatter] interface
+// it is our main point of entry to format the error using the [fmt] package.
+func (err *publicMessage) Format(s fmt.State, verb rune) {
+	format(err, s, verb)
+}
@@ -0,0 +1,37 @@
+package xerrors
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublicMessage(t *testing.T) {
+	err := New("sql: no rows in result set")
+	err = WithPublicMessage(err, "user not found")
+	err = Join(err, "loading user", WithValue("user_id", 42))
+
+	assert.Equal(t, "user not found", PublicMessage(err))
+	assert.Equal(t, "sql: no rows in result set: loading user", err.Error())
+
+	info := Info(err)
+	assert.Equal(t, "sql: no rows in result set: loading user", info.ErrorChain)
+	assert.Equal(t, "*xerrors.errorString", info.Type)
+	assert.Len(t, info.StackTraces, 3)
+	assert.Contains(t, fmt.Sprintf("%+v", err), `public message: "user not found"`)
+}
+
+func TestPublicMessageOutermost(t *testing.T) {
+	err := WithPublicMessage(New("error"), "inner")
+	err = WithPublicMessage(Join(err, "its a wrap"), "outer")
+
+	assert.Equal(t, "outer", PublicMessage(err))
+	assert.Equal(t, "error: its a wrap", err.Error())
+}
+
+func TestPublicMessageDefault(t *testing.T) {
+	assert.Equal(t, DefaultPublicMessage, PublicMessage(New("error")))
+	assert.Equal(t, DefaultPublicMessage, PublicMessage(nil))
+	assert.NoError(t, WithPublicMessage(nil, "msg"))
+}
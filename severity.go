@@ -0,0 +1,117 @@
+package xerrors
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"time"
+)
+
+var (
+	// DefaultSeverity is the severity returned by Severity when an error has no severity.
+	DefaultSeverity = slog.LevelError
+	// ExpectedSeverity is the severity returned by Severity when an expected error has no severity, see IsExpected.
+	ExpectedSeverity = slog.LevelInfo
+)
+
+// WithSeverity returns err with the severity level, used to log it at the relevant level,
+// e.g. slog.LevelInfo for an expected business error. WithSeverity returns nil if err is nil.
+//
+// Custom error types can provide their severity by implementing [slog.Leveler].
+func WithSeverity(err error, level slog.Level) error {
+	if err == nil {
+		return nil
+	}
+	e := &severity{
+		err:   err,
+		level: level,
+	}
+	created(CreatedByWrap, e)
+	return e
+}
+
+// Severity returns the highest severity of the error chain,
+// ExpectedSeverity if there is none and the error is expected, DefaultSeverity otherwise.
+func Severity(err error) slog.Level {
+	found := false
+	var level slog.Level
+	errors := FlattenErrors(err)
+	for i := range errors {
+		l, ok := errors[i].(slog.Leveler)
+		if !ok {
+			continue
+		}
+		if !found || l.Level() > level {
+			level = l.Level()
+		}
+		found = true
+	}
+	if !found {
+		if IsExpected(err) {
+			return ExpectedSeverity
+		}
+		return DefaultSeverity
+	}
+	return level
+}
+
+// LogError logs err with logger at the level returned by Severity, the message being the one of err.
+// The slog.Default logger is used if logger is nil, nothing is logged if err is nil.
+func LogError(ctx context.Context, logger *slog.Logger, err error) {
+	if err == nil {
+		return
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	level := Severity(err)
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+
+	// the record is built here so that its source is the caller of LogError.
+	var pcs [1]uintptr
+	runtime.Callers(2, pcs[:])
+	r := slog.NewRecord(time.Now(), level, err.Error(), pcs[0])
+	r.AddAttrs(slog.Any("error", err))
+	_ = logger.Handler().Handle(ctx, r)
+}
+
+type severity struct {
+	err   error
+	level slog.Level
+}
+
+// Level implements the [slog.Leveler] interface.
+func (err *severity) Level() slog.Level {
+	return err.level
+}
+
+func (err *severity) Unwrap() error {
+	return err.err
+}
+
+func (err *severity) Error() string {
+	return stringify(err)
+}
+
+func (err *severity) message(verbose bool) string {
+	if !verbose {
+		return ""
+	}
+	return "\nseverity: " + err.level.String()
+}
+
+// LogValue implements the [slog.LogValuer] interface
+// it is our main point of entry to format the error as an attribute of a [slog.Record].
+func (err *severity) LogValue() slog.Value {
+	return logValue(err)
+}
+
+// Format implements the [fmt.Formatter] interface
+// it is our main point of entry to format the error using the [fmt] package.
+func (err *severity) Format(s fmt.State, verb rune) {
+	format(err, s, verb)
+}
@@ -0,0 +1,91 @@
+package xerrors
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type leveledError struct{}
+
+func (leveledError) Error() string     { return "leveled" }
+func (leveledError) Level() slog.Level { return slog.LevelWarn }
+
+func TestSeverity(t *testing.T) {
+	notFound := WithSeverity(New("user not found"), slog.LevelInfo)
+	unreachable := WithSeverity(New("database unreachable"), slog.LevelError+4)
+
+	for name, test := range map[string]struct {
+		err  error
+		want slog.Level
+	}{
+		"nil":       {err: nil, want: slog.LevelError},
+		"default":   {err: New("error"), want: slog.LevelError},
+		"info":      {err: notFound, want: slog.LevelInfo},
+		"joined":    {err: Join(notFound, "loading user"), want: slog.LevelInfo},
+		"highest":   {err: Join(notFound, unreachable), want: slog.LevelError + 4},
+		"wrapped":   {err: fmt.Errorf("wrapped: %w", notFound), want: slog.LevelInfo},
+		"outermost": {err: WithSeverity(notFound, slog.LevelDebug), want: slog.LevelInfo},
+		"leveler":   {err: Join(leveledError{}, notFound), want: slog.LevelWarn},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.want, Severity(test.err))
+		})
+	}
+}
+
+func TestWithSeverity(t *testing.T) {
+	err := WithSeverity(New("user not found"), slog.LevelInfo)
+
+	assert.Equal(t, "user not found", err.Error())
+	assert.Contains(t, fmt.Sprintf("%+v", err), "severity: INFO")
+	assert.Equal(t, "*xerrors.errorString", Info(err).Type)
+	assert.NoError(t, WithSeverity(nil, slog.LevelInfo))
+}
+
+func TestLogError(t *testing.T) {
+	for name, test := range map[string]struct {
+		err       error
+		wantLevel string
+	}{
+		"info":    {err: WithSeverity(New("user not found"), slog.LevelInfo), wantLevel: "INFO"},
+		"default": {err: New("database unreachable"), wantLevel: "ERROR"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{AddSource: true}))
+
+			LogError(context.Background(), logger, test.err)
+
+			var m struct {
+				Level  string         `json:"level"`
+				Msg    string         `json:"msg"`
+				Error  map[string]any `json:"error"`
+				Source struct {
+					File string `json:"file"`
+				} `json:"source"`
+			}
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &m))
+			assert.Equal(t, test.wantLevel, m.Level)
+			assert.Equal(t, test.err.Error(), m.Msg)
+			assert.Equal(t, test.err.Error(), m.Error["message"])
+			assert.Equal(t, "severity_test.go", filepath.Base(m.Source.File))
+		})
+	}
+}
+
+func TestLogErrorDisabled(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelWarn}))
+
+	LogError(context.Background(), logger, WithSeverity(New("user not found"), slog.LevelInfo))
+	LogError(context.Background(), logger, nil)
+	assert.Empty(t, buf.String())
+}
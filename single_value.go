@@ -1,6 +1,9 @@
 package xerrors
 
-import "fmt"
+import (
+	"fmt"
+	"log/slog"
+)
 
 // Valuer is an interface that allows custom error types to associate a single key-value pair with an error.
 // Implement this interface in your custom error type to provide specific metadata for the error.
@@ -12,10 +15,12 @@ type Valuer interface {
 
 // WithValue return an error that contains a value.
 func WithValue(key string, val any) error {
-	return &value{
+	err := &value{
 		key:   key,
 		value: val,
 	}
+	created(CreatedByValue, err)
+	return err
 }
 
 type value struct {
@@ -27,6 +32,12 @@ func (err *value) Value() (key string, value any) {
 	return err.key, err.value
 }
 
+// LogValue implements the [slog.LogValuer] interface
+// it is our main point of entry to format the error as an attribute of a [slog.Record].
+func (err *value) LogValue() slog.Value {
+	return logValue(err)
+}
+
 // Format implements the [fmt.Formatter] interface
 // it is our main point of entry to format the error using the [fmt] package.
 func (err *value) Format(s fmt.State, verb rune) {
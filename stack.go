@@ -1,10 +1,12 @@
 package xerrors
 
 import (
-	"errors"
 	"fmt"
+	"log/slog"
 	"runtime"
 	"strings"
+
+	pkgerrors "github.com/pkg/errors"
 )
 
 // Frames is a slice of uintptrs representing stack frames.
@@ -12,28 +14,30 @@ type Frames []uintptr
 
 // Frames returns the list of Frame objects associated with the Frames.
 func (s Frames) Frames() []Frame {
-	r := make([]Frame, len(s))
+	if len(s) == 0 {
+		return []Frame{}
+	}
+
+	r := make([]Frame, 0, len(s))
 	f := runtime.CallersFrames(s)
-	n := 0
 
 	for more := true; more; {
 		var frame runtime.Frame
 		frame, more = f.Next()
-		r[n] = Frame{
+		r = append(r, Frame{
 			File:     frame.File,
 			Line:     frame.Line,
 			Function: frame.Function,
-		}
-		n++
+		})
 	}
 	return r
 }
 
 // Frame represents a single stack frame with file, line, and function information.
 type Frame struct {
-	File     string
-	Line     int
-	Function string
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Function string `json:"function"`
 }
 
 // String returns a string representation of the Frame.
@@ -46,6 +50,39 @@ func (s Frame) String() string {
 	return builder.String()
 }
 
+// Package returns the import path of the package of the function of the frame, e.g. "net/http".
+func (s Frame) Package() string {
+	pkg, _ := splitFunction(s.Function)
+	return pkg
+}
+
+// Name returns the name of the function of the frame without its package path, e.g. "(*Server).Serve".
+func (s Frame) Name() string {
+	_, name := splitFunction(s.Function)
+	return name
+}
+
+// StandardLibrary reports whether the function of the frame belongs to the standard library.
+func (s Frame) StandardLibrary() bool {
+	pkg := s.Package()
+	if pkg == "main" {
+		return false
+	}
+	// The first element of the import path of the packages of the standard library has no dot.
+	first, _, _ := strings.Cut(pkg, "/")
+	return !strings.Contains(first, ".")
+}
+
+// splitFunction splits a fully qualified function name into its package path and its name.
+func splitFunction(function string) (pkg, name string) {
+	slash := strings.LastIndex(function, "/")
+	dot := strings.Index(function[slash+1:], ".")
+	if dot < 0 {
+		return "", function
+	}
+	return function[:slash+1+dot], function[slash+2+dot:]
+}
+
 type stack struct {
 	err     error
 	callers Frames
@@ -84,10 +121,30 @@ func (err *stack) Unwrap() error {
 	return err.err
 }
 
+// Cause implements the causer interface of github.com/pkg/errors.
+func (err *stack) Cause() error {
+	return err.err
+}
+
 func (err *stack) StackFrames() Frames {
 	return err.callers
 }
 
+// StackTrace implements the stackTracer interface of github.com/pkg/errors.
+func (err *stack) StackTrace() pkgerrors.StackTrace {
+	st := make(pkgerrors.StackTrace, len(err.callers))
+	for i, pc := range err.callers {
+		st[i] = pkgerrors.Frame(pc)
+	}
+	return st
+}
+
+// LogValue implements the [slog.LogValuer] interface
+// it is our main point of entry to format the error as an attribute of a [slog.Record].
+func (err *stack) LogValue() slog.Value {
+	return logValue(err)
+}
+
 // Format implements the [fmt.Formatter] interface
 // it is our main point of entry to format the error using the [fmt] package.
 func (err *stack) Format(s fmt.State, verb rune) {
@@ -95,18 +152,34 @@ func (err *stack) Format(s fmt.State, verb rune) {
 }
 
 // StackFrames returns the list of *Frames associated to an error.
+// Besides the stacks captured by this package, the stacks of the errors implementing
+// StackTrace() from github.com/pkg/errors or Callers() []uintptr are found.
 func StackFrames(err error) *Frames {
-	var fss *Frames
-	for ; err != nil; err = errors.Unwrap(err) {
-		var errS *stack
-		ok := errors.As(err, &errS)
-		if ok {
-			fs := errS.StackFrames()
+	errors := FlattenErrors(err)
+	for i := range errors {
+		if fs, ok := stackOf(errors[i]); ok {
 			return &fs
 		}
+	}
+	return nil
+}
 
+// stackOf returns the stack held by err itself, without looking at the errors it wraps.
+func stackOf(err error) (Frames, bool) {
+	switch et := err.(type) {
+	case *stack:
+		return et.callers, true
+	case interface{ StackTrace() pkgerrors.StackTrace }:
+		st := et.StackTrace()
+		fs := make(Frames, len(st))
+		for i := range st {
+			fs[i] = uintptr(st[i])
+		}
+		return fs, true
+	case interface{ Callers() []uintptr }:
+		return et.Callers(), true
 	}
-	return fss
+	return nil, false
 }
 
 func callers(skip int) Frames {
@@ -115,6 +188,17 @@ func callers(skip int) Frames {
 	return pc[:n]
 }
 
+// WithStack returns err with the stack trace of the caller, even if err already has one,
+// such as a sentinel error created by New whose stack trace is the one of the package initialization.
+// WithStack returns nil if err is nil.
+func WithStack(err error) error {
+	err = withStack(err, 2)
+	if err != nil {
+		created(CreatedByWrap, err)
+	}
+	return err
+}
+
 func ensureStack(err error, skip int) error {
 	if !hasStack(err) {
 		err = withStack(err, skip+1)
@@ -123,15 +207,17 @@ func ensureStack(err error, skip int) error {
 }
 
 func hasStack(err error) bool {
-	return Is(err, &stack{})
+	return StackFrames(err) != nil
 }
 
 func withStack(err error, skip int) error {
 	if err == nil {
 		return nil
 	}
-	return &stack{
+	errS := &stack{
 		err:     err,
 		callers: callers(skip + 1),
 	}
+	created(CreatedByStack, errS)
+	return errS
 }
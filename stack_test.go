@@ -1,9 +1,14 @@
 package xerrors
 
 import (
+	"fmt"
+	"io"
+	"runtime"
 	"testing"
 
+	pkgerrors "github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestStack(t *testing.T) {
@@ -21,3 +26,98 @@ func TestStackNil(t *testing.T) {
 	err := withStack(nil, 0)
 	assert.NoError(t, err)
 }
+
+func TestStackPkgErrors(t *testing.T) {
+	err := New("error")
+
+	var st interface{ StackTrace() pkgerrors.StackTrace }
+	require.ErrorAs(t, err, &st)
+	assert.Contains(t, fmt.Sprintf("%+v", st.StackTrace()), "github.com/emilien-puget/xerrors.TestStackPkgErrors")
+
+	wrapped := Join(io.EOF, "its a wrap")
+	assert.Equal(t, io.EOF, pkgerrors.Cause(wrapped))
+	assert.Equal(t, io.EOF, Cause(wrapped))
+	assert.Equal(t, io.EOF, Cause(pkgerrors.Wrap(io.EOF, "its a wrap")))
+	assert.Equal(t, io.EOF, Cause(fmt.Errorf("its a wrap: %w", wrapped)))
+	assert.NoError(t, Cause(nil))
+}
+
+func pkgErrorsOrigin() error {
+	return pkgerrors.New("error")
+}
+
+func TestStackForeignPkgErrors(t *testing.T) {
+	err := Join(pkgErrorsOrigin(), "its a wrap")
+
+	frames := StackFrames(err).Frames()
+	assert.Equal(t, "github.com/emilien-puget/xerrors.pkgErrorsOrigin", frames[0].Function)
+
+	info := Info(err)
+	assert.Equal(t, "github.com/emilien-puget/xerrors.pkgErrorsOrigin", info.Frames[0].Function)
+	assert.Contains(t, info.StackTraces[0], "github.com/emilien-puget/xerrors.pkgErrorsOrigin")
+}
+
+type callersError struct {
+	pcs []uintptr
+}
+
+func (e *callersError) Error() string {
+	return "callers"
+}
+
+func (e *callersError) Callers() []uintptr {
+	return e.pcs
+}
+
+func TestStackForeignCallers(t *testing.T) {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(1, pcs)
+	err := Join(&callersError{pcs: pcs[:n]}, "its a wrap")
+
+	frames := StackFrames(err).Frames()
+	assert.Equal(t, "github.com/emilien-puget/xerrors.TestStackForeignCallers", frames[0].Function)
+}
+
+func TestStackFramesNone(t *testing.T) {
+	assert.Nil(t, StackFrames(io.EOF))
+}
+
+func TestFramePackage(t *testing.T) {
+	tests := map[string]struct {
+		function        string
+		wantPackage     string
+		wantName        string
+		standardLibrary bool
+	}{
+		"standard_library": {
+			function:        "runtime.goexit",
+			wantPackage:     "runtime",
+			wantName:        "goexit",
+			standardLibrary: true,
+		},
+		"method": {
+			function:    "github.com/emilien-puget/xerrors/xslog.(*Handler).Handle",
+			wantPackage: "github.com/emilien-puget/xerrors/xslog",
+			wantName:    "(*Handler).Handle",
+		},
+		"main": {
+			function:    "main.main.func1",
+			wantPackage: "main",
+			wantName:    "main.func1",
+		},
+		"no_package": {
+			function:        "nodot",
+			wantPackage:     "",
+			wantName:        "nodot",
+			standardLibrary: true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			frame := Frame{Function: test.function}
+			assert.Equal(t, test.wantPackage, frame.Package())
+			assert.Equal(t, test.wantName, frame.Name())
+			assert.Equal(t, test.standardLibrary, frame.StandardLibrary())
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package xerrors
+
+import (
+	"strconv"
+	"strings"
+)
+
+// TreeOptions configures how Tree renders an error.
+type TreeOptions struct {
+	// ASCII draws the branches with ASCII characters instead of box-drawing ones.
+	ASCII bool
+	// MaxDepth is the maximum depth of the rendered branches, deeper branches are summarized, zero means no limit.
+	MaxDepth int
+	// MaxChildren is the maximum number of children rendered for a branch, the others are summarized, zero means no limit.
+	MaxChildren int
+}
+
+type treeGlyphs struct {
+	branch, last, pipe, space, ellipsis string
+}
+
+var (
+	boxGlyphs   = treeGlyphs{branch: "├── ", last: "└── ", pipe: "│   ", space: "    ", ellipsis: "…"}
+	asciiGlyphs = treeGlyphs{branch: "|-- ", last: "`-- ", pipe: "|   ", space: "    ", ellipsis: "..."}
+)
+
+// Tree renders err as an indented tree: every joined error is a branch showing its message,
+// its values and its stack trace at its depth.
+// It is the rendering used by the %+v verb.
+func Tree(err error) string {
+	return TreeOptions{}.Tree(err)
+}
+
+// Tree renders err as an indented tree using the options.
+func (o TreeOptions) Tree(err error) string {
+	return o.tree(err, false)
+}
+
+// tree renders err as an indented tree, color styles the lines with ANSI escape sequences.
+func (o TreeOptions) tree(err error, color bool) string {
+	if err == nil {
+		return ""
+	}
+
+	glyphs := boxGlyphs
+	if o.ASCII {
+		glyphs = asciiGlyphs
+	}
+
+	builder := bufferPool.Get().(*strings.Builder)
+	builder.Reset()
+	defer bufferPool.Put(builder)
+
+	r := treeRenderer{opts: o, glyphs: glyphs, color: color, builder: builder}
+	r.render(err, "", "", 0)
+
+	return strings.TrimSuffix(builder.String(), "\n")
+}
+
+type treeRenderer struct {
+	opts    TreeOptions
+	glyphs  treeGlyphs
+	color   bool
+	builder *strings.Builder
+}
+
+// treeLine is a line of a node, style holds the ANSI SGR parameters used when the tree is colorized.
+type treeLine struct {
+	text  string
+	style string
+}
+
+// render writes the node of err, prefix is written before its label and childPrefix before its details and children.
+func (r *treeRenderer) render(err error, prefix, childPrefix string, depth int) {
+	label, details, children := treeNode(err)
+
+	// the continuation lines of a multi-line message, such as the one of errors.Join, are aligned as details.
+	lines := strings.Split(label.text, "\n")
+	r.line(prefix, treeLine{text: lines[0], style: label.style})
+	for _, line := range lines[1:] {
+		r.line(childPrefix, treeLine{text: line, style: label.style})
+	}
+	for _, detail := range details {
+		r.line(childPrefix, detail)
+	}
+
+	if len(children) == 0 {
+		return
+	}
+	if r.opts.MaxDepth > 0 && depth >= r.opts.MaxDepth {
+		r.more(childPrefix, len(children))
+		return
+	}
+
+	shown := children
+	if r.opts.MaxChildren > 0 && len(children) > r.opts.MaxChildren {
+		shown = children[:r.opts.MaxChildren]
+	}
+	for i, child := range shown {
+		if i == len(children)-1 {
+			r.render(child, childPrefix+r.glyphs.last, childPrefix+r.glyphs.space, depth+1)
+			continue
+		}
+		r.render(child, childPrefix+r.glyphs.branch, childPrefix+r.glyphs.pipe, depth+1)
+	}
+	if len(shown) < len(children) {
+		r.more(childPrefix, len(children)-len(shown))
+	}
+}
+
+func (r *treeRenderer) more(prefix string, n int) {
+	r.line(prefix+r.glyphs.last, treeLine{text: r.glyphs.ellipsis + " " + strconv.Itoa(n) + " more", style: styleDim})
+}
+
+func (r *treeRenderer) line(prefix string, line treeLine) {
+	r.builder.WriteString(prefix)
+	if r.color && line.style != "" {
+		r.builder.WriteString("\x1b[" + line.style + "m" + line.text + "\x1b[0m\n")
+		return
+	}
+	r.builder.WriteString(line.text)
+	r.builder.WriteByte('\n')
+}
+
+// treeNode walks the chain of err until it reaches joined errors,
+// the details are the verbose messages of the wrappers found along the way, such as the stack trace or the values.
+func treeNode(err error) (label treeLine, details []treeLine, children []error) {
+	label = treeLine{text: err.Error(), style: styleMessage}
+
+	for cur := err; cur != nil; cur = Unwrap(cur) {
+		if f, ok := cur.(formattable); ok {
+			if _, ok := cur.(*joinError); !ok {
+				if verbose := f.message(true); verbose != f.message(false) {
+					details = append(details, detailLines(cur, verbose)...)
+				}
+			}
+		}
+		if u, ok := cur.(interface{ Unwrap() []error }); ok {
+			for _, child := range u.Unwrap() {
+				if child != nil {
+					children = append(children, child)
+				}
+			}
+			break
+		}
+	}
+
+	if label.text == "" && len(details) > 0 {
+		label, details = details[0], details[1:]
+	}
+
+	return label, details, children
+}
+
+// detailLines splits the verbose message of err in lines styled after the kind of err,
+// the frames of a stack trace being emphasized when they belong to the main module and dimmed otherwise.
+func detailLines(err error, message string) []treeLine {
+	var frames []Frame
+	style := ""
+	switch err.(type) {
+	case *value, *multiValue, *attrValues:
+		style = styleValue
+	case *stack:
+		frames = StackFrames(err).Frames()
+	}
+
+	var lines []treeLine
+	for _, line := range strings.Split(message, "\n") {
+		if line == "" {
+			continue
+		}
+		l := treeLine{text: strings.ReplaceAll(line, "\t", "  "), style: style}
+		// the first line of a stack trace is its header, the others are its frames in order.
+		if frames != nil && len(lines) > 0 && len(lines) <= len(frames) {
+			l.style = styleLibraryFrame
+			if inModule(frames[len(lines)-1].Function) {
+				l.style = styleModuleFrame
+			}
+		}
+		lines = append(lines, l)
+	}
+	return lines
+}
@@ -0,0 +1,115 @@
+package xerrors
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTree(t *testing.T) {
+	errA := errors.New("a")
+	errB := errors.New("b")
+	errC := errors.New("c")
+	nested := &joinError{
+		err: &joinError{err: errA, errs: []error{errB, WithValue("key", "value")}},
+		errs: []error{
+			errC,
+			errors.Join(errA, errB),
+		},
+	}
+
+	for name, test := range map[string]struct {
+		err      error
+		opts     TreeOptions
+		expected string
+	}{
+		"nil": {
+			err:      nil,
+			expected: "",
+		},
+		"leaf": {
+			err:      errA,
+			expected: "a",
+		},
+		"value_only": {
+			err:      WithValue("key", "value"),
+			expected: `value: key "value"`,
+		},
+		"public_message": {
+			err:      WithPublicMessage(errA, "oops"),
+			expected: "a\npublic message: \"oops\"",
+		},
+		"nested": {
+			err: nested,
+			expected: `a: b: c + a
+b
+├── a: b
+│   ├── a
+│   ├── b
+│   └── value: key "value"
+├── c
+└── a
+    b
+    ├── a
+    └── b`,
+		},
+		"ascii": {
+			err:  nested,
+			opts: TreeOptions{ASCII: true},
+			expected: `a: b: c + a
+b
+|-- a: b
+|   |-- a
+|   |-- b
+|   ` + "`" + `-- value: key "value"
+|-- c
+` + "`" + `-- a
+    b
+    |-- a
+    ` + "`" + `-- b`,
+		},
+		"max_depth": {
+			err:  nested,
+			opts: TreeOptions{MaxDepth: 1},
+			expected: `a: b: c + a
+b
+├── a: b
+│   └── … 3 more
+├── c
+└── a
+    b
+    └── … 2 more`,
+		},
+		"max_children": {
+			err:  nested,
+			opts: TreeOptions{MaxChildren: 1},
+			expected: `a: b: c + a
+b
+├── a: b
+│   ├── a
+│   └── … 2 more
+└── … 2 more`,
+		},
+		"wrapped_join": {
+			err: fmt.Errorf("wrapped: %w", nested.err),
+			expected: `wrapped: a: b
+├── a
+├── b
+└── value: key "value"`,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.opts.Tree(test.err))
+		})
+	}
+}
+
+func TestTreeStack(t *testing.T) {
+	err := Join(New("error"), "its a wrap")
+
+	tree := Tree(err)
+	assert.Regexp(t, "^error: its a wrap\n├── error\n│   stack\n│     github.com/emilien-puget/xerrors.TestTreeStack [^\n]+tree_test.go:110\n", tree)
+	assert.Equal(t, tree, fmt.Sprintf("%+v", err))
+}
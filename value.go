@@ -5,21 +5,25 @@ func Values(err error) map[string]any {
 	errors := FlattenErrors(err)
 	vals := make(map[string]any)
 	for i := range errors {
-		switch et := errors[i].(type) {
-		case Valuer:
-			key, v := et.Value()
+		errorValues(errors[i], func(key string, v any) {
 			if _, ok := vals[key]; ok {
-				continue
+				return
 			}
 			vals[key] = v
-		case MultiValuer:
-			for s, a := range et.Value() {
-				if _, ok := vals[s]; ok {
-					continue
-				}
-				vals[s] = a
-			}
-		}
+		})
 	}
 	return vals
 }
+
+// errorValues calls fn for every value held by err itself, not by the errors it wraps, in the order they are rendered.
+func errorValues(err error, fn func(key string, v any)) {
+	switch et := err.(type) {
+	case Valuer:
+		fn(et.Value())
+	case MultiValuer:
+		values := et.Value()
+		for _, key := range multiValueKeys(et) {
+			fn(key, values[key])
+		}
+	}
+}
@@ -0,0 +1,15 @@
+// Command xerrorsvet reports misuses of xerrors, see the xerrorscheck package for the list of checks.
+//
+// It can be run on its own or by go vet:
+//
+//	go vet -vettool=$(which xerrorsvet) ./...
+package main
+
+import (
+	"github.com/emilien-puget/xerrors/xerrorscheck"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(xerrorscheck.Analyzer)
+}
@@ -0,0 +1,110 @@
+package a
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+	"strconv"
+
+	"github.com/emilien-puget/xerrors"
+)
+
+var ErrNotFound = xerrors.New("not found")
+
+const keyUser = "user"
+
+func local() error {
+	return ErrNotFound
+}
+
+func returnForeignCall(name string) error {
+	return os.Remove(name) // want `error returned by os.Remove is returned without being wrapped, use xerrors.Join`
+}
+
+func returnForeignVar(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, err // want `error returned by strconv.Atoi is returned without being wrapped, use xerrors.Join`
+	}
+	err = local()
+	return n, err
+}
+
+func returnWrapped(name string) error {
+	if err := os.Remove(name); err != nil {
+		return xerrors.Join(err, "removing file")
+	}
+	if err := local(); err != nil {
+		return err
+	}
+	f := func() error {
+		return os.Remove(name) // want `error returned by os.Remove is returned without being wrapped, use xerrors.Join`
+	}
+	return f()
+}
+
+func compare(err error) bool {
+	if err == io.EOF { // want `comparison with == against the sentinel error EOF, use xerrors.Is`
+		return true
+	}
+	if ErrNotFound != err { // want `comparison with != against the sentinel error ErrNotFound, use xerrors.Is`
+		return true
+	}
+	if err == nil {
+		return false
+	}
+	return xerrors.Is(err, io.EOF)
+}
+
+type stringer struct{}
+
+func (stringer) String() string { return "stringer" }
+
+type ptrStringer struct{}
+
+func (*ptrStringer) String() string { return "ptrStringer" }
+
+func join(ctx context.Context, errs ...any) error {
+	_ = xerrors.Join(io.EOF, "msg", ErrNotFound, nil, stringer{}, slog.Int("status", 500), map[string]any{"k": 1}, []error{io.EOF})
+	_ = xerrors.Join(io.EOF, 42) // want `argument of type int is not supported by Join and is joined as an unsupported argument`
+	_ = xerrors.Join(io.EOF, &ptrStringer{})
+	_ = xerrors.Join(io.EOF, ptrStringer{})         // want `argument of type a.ptrStringer is not supported by Join and is joined as an unsupported argument`
+	_ = xerrors.JoinCtx(ctx, io.EOF, []string{"a"}) // want `argument of type \[\]string is not supported by JoinCtx and is joined as an unsupported argument`
+	return xerrors.Join(io.EOF, errs...)
+}
+
+func collide(id int) error {
+	return xerrors.Join(io.EOF,
+		xerrors.WithValue(keyUser, id),
+		xerrors.WithValue("user", id), // want `key "user" is already set by a value of this Join, only one of them is kept`
+		xerrors.WithValues(map[string]any{
+			"other": 1,
+			"user":  2, // want `key "user" is already set by a value of this Join, only one of them is kept`
+		}),
+	)
+}
+
+func collideOrdered(id int) error {
+	return xerrors.Join(io.EOF,
+		slog.Int("status", 500),
+		xerrors.WithOrderedValues(
+			"user", id,
+			slog.Int("status", 404), // want `key "status" is already set by a value of this Join, only one of them is kept`
+			"count", 1,
+		),
+		xerrors.WithOrderedValues("count", 2), // want `key "count" is already set by a value of this Join, only one of them is kept`
+		xerrors.WithAttrs(
+			slog.String("user", "bob"), // want `key "user" is already set by a value of this Join, only one of them is kept`
+		),
+	)
+}
+
+func wrapVerb(err error) error {
+	log.Printf("failed: %w", err)      // want `log.Printf does not support the %w verb, only fmt.Errorf does`
+	_ = fmt.Sprintf("failed: %w", err) // want `fmt.Sprintf does not support the %w verb, only fmt.Errorf does`
+	_ = fmt.Errorf("failed: %w", err)
+	return xerrors.New("failed: %w") // want `xerrors.New does not support the %w verb, only fmt.Errorf does`
+}
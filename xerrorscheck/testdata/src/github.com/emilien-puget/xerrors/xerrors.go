@@ -0,0 +1,23 @@
+// Package xerrors is a stub of github.com/emilien-puget/xerrors for the tests of the analyzer.
+package xerrors
+
+import (
+	"context"
+	"log/slog"
+)
+
+func New(msg string) error { return nil }
+
+func Join(ogErr error, errs ...any) error { return nil }
+
+func JoinCtx(ctx context.Context, ogErr error, errs ...any) error { return nil }
+
+func WithValue(key string, val any) error { return nil }
+
+func WithValues(v map[string]any) error { return nil }
+
+func WithOrderedValues(args ...any) error { return nil }
+
+func WithAttrs(attrs ...slog.Attr) error { return nil }
+
+func Is(err, target error) bool { return false }
@@ -0,0 +1,371 @@
+// Package xerrorscheck defines an analyzer reporting misuses of xerrors.
+//
+// It reports:
+//   - errors returned by functions of other packages that are returned without being wrapped,
+//   - comparisons of errors with == or != against sentinel errors instead of xerrors.Is,
+//   - arguments of Join of an unsupported type, which are joined as "unsupported argument" errors,
+//   - constant keys set several times by the values of the same Join, only one of them being kept,
+//   - format strings using %w passed to another function than fmt.Errorf.
+package xerrorscheck
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+const xerrorsPath = "github.com/emilien-puget/xerrors"
+
+// Analyzer reports misuses of xerrors.
+var Analyzer = &analysis.Analyzer{
+	Name:     "xerrorscheck",
+	Doc:      "report misuses of github.com/emilien-puget/xerrors",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+var errorType = types.Universe.Lookup("error").Type().Underlying().(*types.Interface)
+
+func run(pass *analysis.Pass) (any, error) {
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	nodeFilter := []ast.Node{
+		(*ast.FuncDecl)(nil),
+		(*ast.FuncLit)(nil),
+		(*ast.BinaryExpr)(nil),
+		(*ast.CallExpr)(nil),
+	}
+	insp.Preorder(nodeFilter, func(n ast.Node) {
+		switch n := n.(type) {
+		case *ast.FuncDecl:
+			if n.Body != nil {
+				checkReturns(pass, pass.TypesInfo.Defs[n.Name].Type().(*types.Signature), n.Body)
+			}
+		case *ast.FuncLit:
+			checkReturns(pass, pass.TypesInfo.TypeOf(n).(*types.Signature), n.Body)
+		case *ast.BinaryExpr:
+			checkSentinelComparison(pass, n)
+		case *ast.CallExpr:
+			checkJoinArgs(pass, n)
+			checkWrapVerb(pass, n)
+		}
+	})
+	return nil, nil
+}
+
+// checkReturns reports the errors of other packages returned as is by the function of body.
+func checkReturns(pass *analysis.Pass, sig *types.Signature, body *ast.BlockStmt) {
+	results := sig.Results()
+	if results.Len() == 0 || !isError(results.At(results.Len()-1).Type()) {
+		return
+	}
+
+	// foreign holds the variables last assigned with the result of a function of another package.
+	foreign := make(map[types.Object]string)
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.AssignStmt:
+			trackAssign(pass, foreign, n)
+		case *ast.ReturnStmt:
+			if len(n.Results) != results.Len() {
+				return true
+			}
+			res := ast.Unparen(n.Results[len(n.Results)-1])
+			switch res := res.(type) {
+			case *ast.CallExpr:
+				if fn := foreignFunc(pass, res); fn != "" {
+					pass.Reportf(res.Pos(), "error returned by %s is returned without being wrapped, use xerrors.Join", fn)
+				}
+			case *ast.Ident:
+				if fn, ok := foreign[pass.TypesInfo.Uses[res]]; ok {
+					pass.Reportf(res.Pos(), "error returned by %s is returned without being wrapped, use xerrors.Join", fn)
+				}
+			}
+		}
+		return true
+	})
+}
+
+func trackAssign(pass *analysis.Pass, foreign map[types.Object]string, assign *ast.AssignStmt) {
+	var fn string
+	if len(assign.Rhs) == 1 {
+		if call, ok := ast.Unparen(assign.Rhs[0]).(*ast.CallExpr); ok {
+			fn = foreignFunc(pass, call)
+		}
+	}
+	for i, lhs := range assign.Lhs {
+		id, ok := lhs.(*ast.Ident)
+		if !ok {
+			continue
+		}
+		obj := pass.TypesInfo.ObjectOf(id)
+		if obj == nil {
+			continue
+		}
+		// Only the last error result of a multi-value call is tracked.
+		if fn != "" && i == len(assign.Lhs)-1 && isError(obj.Type()) {
+			foreign[obj] = fn
+		} else {
+			delete(foreign, obj)
+		}
+	}
+}
+
+// foreignFunc returns the name of the function called by call if it belongs to another package than the one analyzed
+// and xerrors, and returns an error as last result.
+func foreignFunc(pass *analysis.Pass, call *ast.CallExpr) string {
+	fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg() == pass.Pkg || isXerrors(fn.Pkg()) {
+		return ""
+	}
+	results := fn.Type().(*types.Signature).Results()
+	if results.Len() == 0 || !isError(results.At(results.Len()-1).Type()) {
+		return ""
+	}
+	return fn.Pkg().Name() + "." + fn.Name()
+}
+
+// checkSentinelComparison reports the comparisons of errors against package-level error variables.
+func checkSentinelComparison(pass *analysis.Pass, expr *ast.BinaryExpr) {
+	if expr.Op != token.EQL && expr.Op != token.NEQ {
+		return
+	}
+	for _, operands := range [][2]ast.Expr{{expr.X, expr.Y}, {expr.Y, expr.X}} {
+		sentinel, other := operands[0], operands[1]
+		v := sentinelVar(pass, sentinel)
+		if v == nil || !isError(pass.TypesInfo.TypeOf(other)) || isNil(pass, other) {
+			continue
+		}
+		pass.Reportf(expr.Pos(), "comparison with %s against the sentinel error %s, use xerrors.Is", expr.Op, v.Name())
+		return
+	}
+}
+
+func sentinelVar(pass *analysis.Pass, expr ast.Expr) *types.Var {
+	var id *ast.Ident
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		id = e
+	case *ast.SelectorExpr:
+		id = e.Sel
+	default:
+		return nil
+	}
+	v, ok := pass.TypesInfo.Uses[id].(*types.Var)
+	if !ok || v.Pkg() == nil || v.Parent() != v.Pkg().Scope() || !isError(v.Type()) {
+		return nil
+	}
+	return v
+}
+
+// checkJoinArgs reports the arguments of Join of an unsupported type and the keys set several times by its values.
+func checkJoinArgs(pass *analysis.Pass, call *ast.CallExpr) {
+	fn := xerrorsFunc(pass, call)
+	var variadic []ast.Expr
+	switch {
+	case fn == "Join" && len(call.Args) > 1:
+		variadic = call.Args[1:]
+	case fn == "JoinCtx" && len(call.Args) > 2:
+		variadic = call.Args[2:]
+	default:
+		return
+	}
+	if call.Ellipsis.IsValid() {
+		return
+	}
+
+	keys := make(map[string]bool)
+	for _, arg := range variadic {
+		if !isJoinable(pass, arg) {
+			pass.Reportf(arg.Pos(), "argument of type %s is not supported by %s and is joined as an unsupported argument", pass.TypesInfo.TypeOf(arg), fn)
+			continue
+		}
+		for _, key := range constantKeys(pass, arg) {
+			if keys[key.value] {
+				pass.Reportf(key.pos, "key %q is already set by a value of this %s, only one of them is kept", key.value, fn)
+			}
+			keys[key.value] = true
+		}
+	}
+}
+
+// isJoinable reports whether arg has one of the types supported by Join:
+// string, error, fmt.Stringer, slog.Attr, map[string]any or []error.
+func isJoinable(pass *analysis.Pass, arg ast.Expr) bool {
+	if isNil(pass, arg) {
+		return true
+	}
+	t := types.Unalias(pass.TypesInfo.TypeOf(arg))
+	if isError(t) || hasStringMethod(t) {
+		return true
+	}
+	switch u := t.(type) {
+	case *types.Basic:
+		return u.Info()&types.IsString != 0
+	case *types.Named:
+		obj := u.Obj()
+		return obj.Pkg() != nil && obj.Pkg().Path() == "log/slog" && obj.Name() == "Attr"
+	case *types.Map:
+		b, ok := u.Key().(*types.Basic)
+		return ok && b.Kind() == types.String && isEmptyInterface(u.Elem())
+	case *types.Slice:
+		return types.Identical(u.Elem(), types.Universe.Lookup("error").Type())
+	}
+	return false
+}
+
+func hasStringMethod(t types.Type) bool {
+	// the method set of t itself, a String method with a pointer receiver does not make a value a fmt.Stringer.
+	sel := types.NewMethodSet(t).Lookup(nil, "String")
+	if sel == nil {
+		return false
+	}
+	fn, ok := sel.Obj().(*types.Func)
+	if !ok {
+		return false
+	}
+	sig := fn.Type().(*types.Signature)
+	if sig.Params().Len() != 0 || sig.Results().Len() != 1 {
+		return false
+	}
+	b, ok := sig.Results().At(0).Type().(*types.Basic)
+	return ok && b.Kind() == types.String
+}
+
+func isEmptyInterface(t types.Type) bool {
+	i, ok := t.Underlying().(*types.Interface)
+	return ok && i.Empty()
+}
+
+type constantKey struct {
+	value string
+	pos   token.Pos
+}
+
+// constantKeys returns the constant keys of the values created by a call to WithValue, WithValues, WithOrderedValues,
+// WithAttrs or to a function of log/slog creating an attribute.
+func constantKeys(pass *analysis.Pass, arg ast.Expr) []constantKey {
+	call, ok := ast.Unparen(arg).(*ast.CallExpr)
+	if !ok || len(call.Args) == 0 {
+		return nil
+	}
+
+	var exprs []ast.Expr
+	if isSlogAttrCall(pass, call) {
+		exprs = append(exprs, call.Args[0])
+	}
+	switch xerrorsFunc(pass, call) {
+	case "WithValue":
+		exprs = append(exprs, call.Args[0])
+	case "WithOrderedValues", "WithAttrs":
+		// the arguments are either an attribute or a key followed by its value.
+		for i := 0; i < len(call.Args); i++ {
+			a := call.Args[i]
+			if c, ok := ast.Unparen(a).(*ast.CallExpr); ok && isSlogAttrCall(pass, c) && len(c.Args) > 0 {
+				exprs = append(exprs, c.Args[0])
+				continue
+			}
+			if b, ok := pass.TypesInfo.TypeOf(a).Underlying().(*types.Basic); ok && b.Info()&types.IsString != 0 {
+				exprs = append(exprs, a)
+				i++
+			}
+		}
+	case "WithValues":
+		lit, ok := ast.Unparen(call.Args[0]).(*ast.CompositeLit)
+		if !ok {
+			return nil
+		}
+		for _, elt := range lit.Elts {
+			if kv, ok := elt.(*ast.KeyValueExpr); ok {
+				exprs = append(exprs, kv.Key)
+			}
+		}
+	}
+
+	var keys []constantKey
+	for _, expr := range exprs {
+		tv, ok := pass.TypesInfo.Types[expr]
+		if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+			continue
+		}
+		keys = append(keys, constantKey{value: constant.StringVal(tv.Value), pos: expr.Pos()})
+	}
+	return keys
+}
+
+// isSlogAttrCall reports whether call calls a function of log/slog returning a slog.Attr, such as slog.String.
+func isSlogAttrCall(pass *analysis.Pass, call *ast.CallExpr) bool {
+	fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != "log/slog" {
+		return false
+	}
+	results := fn.Type().(*types.Signature).Results()
+	if results.Len() != 1 {
+		return false
+	}
+	named, ok := results.At(0).Type().(*types.Named)
+	return ok && named.Obj().Name() == "Attr"
+}
+
+// checkWrapVerb reports the format strings using %w passed to another function than fmt.Errorf.
+func checkWrapVerb(pass *analysis.Pass, call *ast.CallExpr) {
+	fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	if !ok || fn.Pkg() == nil {
+		return
+	}
+	path := fn.Pkg().Path()
+	switch {
+	case path == "fmt" && fn.Name() == "Errorf":
+		return
+	case path == "fmt", path == "log", path == "testing", isXerrors(fn.Pkg()):
+	default:
+		return
+	}
+
+	for _, arg := range call.Args {
+		tv, ok := pass.TypesInfo.Types[arg]
+		if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+			continue
+		}
+		if strings.Contains(constant.StringVal(tv.Value), "%w") {
+			pass.Reportf(arg.Pos(), "%s does not support the %%w verb, only fmt.Errorf does", qualifiedName(fn))
+		}
+	}
+}
+
+// xerrorsFunc returns the name of the function of xerrors called by call, empty if call does not call xerrors.
+func xerrorsFunc(pass *analysis.Pass, call *ast.CallExpr) string {
+	fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != xerrorsPath {
+		return ""
+	}
+	return fn.Name()
+}
+
+func qualifiedName(fn *types.Func) string {
+	if fn.Type().(*types.Signature).Recv() != nil {
+		return fn.FullName()
+	}
+	return fn.Pkg().Name() + "." + fn.Name()
+}
+
+func isXerrors(pkg *types.Package) bool {
+	return pkg.Path() == xerrorsPath || strings.HasPrefix(pkg.Path(), xerrorsPath+"/")
+}
+
+func isError(t types.Type) bool {
+	return t != nil && types.Implements(t, errorType)
+}
+
+func isNil(pass *analysis.Pass, expr ast.Expr) bool {
+	return pass.TypesInfo.Types[expr].IsNil()
+}
@@ -0,0 +1,21 @@
+module github.com/emilien-puget/xerrors/xgrpc
+
+go 1.22.0
+
+require (
+	github.com/emilien-puget/xerrors v0.0.0-20261019171927-9ad8e256b4c3
+	github.com/stretchr/testify v1.9.0
+	google.golang.org/grpc v1.70.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pkg/errors v0.9.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/sys v0.28.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20241202173237-19429a94021a // indirect
+	google.golang.org/protobuf v1.35.2 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/emilien-puget/xerrors => ../
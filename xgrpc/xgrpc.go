@@ -0,0 +1,39 @@
+// Package xgrpc converts errors created with xerrors to gRPC statuses.
+package xgrpc
+
+import (
+	"github.com/emilien-puget/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Status returns a status with the code and the public message of err, nil if err is nil.
+// Only the public message is used, the internal error chain never reaches the client.
+//
+// Server errors, with a code such as codes.Internal or codes.Unavailable, are passed to [xerrors.Report],
+// the hooks deciding how to handle the expected ones, see [xerrors.IsExpected].
+func Status(err error, code codes.Code) *status.Status {
+	if err == nil {
+		return nil
+	}
+	if isServerCode(code) {
+		xerrors.Report(err)
+	}
+	return status.New(code, xerrors.PublicMessage(err))
+}
+
+// Error returns an error with the code and the public message of err, to be returned by a gRPC handler.
+// It returns nil if err is nil, see Status.
+func Error(err error, code codes.Code) error {
+	return Status(err, code).Err()
+}
+
+// isServerCode reports whether code is caused by the server rather than the client,
+// those are the codes mapped to a 5xx HTTP status.
+func isServerCode(code codes.Code) bool {
+	switch code {
+	case codes.Unknown, codes.DeadlineExceeded, codes.Unimplemented, codes.Internal, codes.Unavailable, codes.DataLoss:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package xgrpc
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/emilien-puget/xerrors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestError(t *testing.T) {
+	for name, test := range map[string]struct {
+		err  error
+		want string
+	}{
+		"public": {
+			err:  xerrors.WithPublicMessage(xerrors.New("sql: no rows in result set"), "user not found"),
+			want: "user not found",
+		},
+		"internal": {
+			err:  xerrors.New("sql: connection refused"),
+			want: xerrors.DefaultPublicMessage,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := Error(test.err, codes.NotFound)
+
+			s, ok := status.FromError(err)
+			require.True(t, ok)
+			assert.Equal(t, codes.NotFound, s.Code())
+			assert.Equal(t, test.want, s.Message())
+		})
+	}
+}
+
+func TestErrorNil(t *testing.T) {
+	assert.NoError(t, Error(nil, codes.Internal))
+	assert.Nil(t, Status(nil, codes.Internal))
+}
+
+func TestErrorReport(t *testing.T) {
+	var mu sync.Mutex
+	reported := map[error]bool{}
+	xerrors.OnReport(func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		reported[err] = true
+	})
+
+	for name, test := range map[string]struct {
+		err          error
+		code         codes.Code
+		wantReported bool
+	}{
+		"server_error": {
+			err:          xerrors.New("sql: connection refused"),
+			code:         codes.Internal,
+			wantReported: true,
+		},
+		"client_error": {
+			err:  xerrors.New("invalid id"),
+			code: codes.InvalidArgument,
+		},
+		"expected": {
+			err:          xerrors.Expected(xerrors.New("upstream overloaded")),
+			code:         codes.Unavailable,
+			wantReported: true,
+		},
+		"canceled": {
+			err:          xerrors.Join(context.Canceled, "call aborted"),
+			code:         codes.Internal,
+			wantReported: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_ = Error(test.err, test.code)
+
+			mu.Lock()
+			defer mu.Unlock()
+			assert.Equal(t, test.wantReported, reported[test.err])
+		})
+	}
+}
@@ -0,0 +1,20 @@
+// Package xhttp writes errors created with xerrors to HTTP responses.
+package xhttp
+
+import (
+	"net/http"
+
+	"github.com/emilien-puget/xerrors"
+)
+
+// Error replies to the request with the public message of err and the HTTP code, like [http.Error].
+// Only the public message is written, the internal error chain never reaches the client.
+//
+// Server errors, with a code of 500 or more, are passed to [xerrors.Report], the hooks deciding how to handle the
+// expected ones, see [xerrors.IsExpected].
+func Error(w http.ResponseWriter, err error, code int) {
+	if code >= http.StatusInternalServerError {
+		xerrors.Report(err)
+	}
+	http.Error(w, xerrors.PublicMessage(err), code)
+}
@@ -0,0 +1,93 @@
+package xhttp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/emilien-puget/xerrors"
+	"github.com/emilien-puget/xerrors/xmetrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestError(t *testing.T) {
+	for name, test := range map[string]struct {
+		err  error
+		want string
+	}{
+		"public": {
+			err:  xerrors.WithPublicMessage(xerrors.New("sql: no rows in result set"), "user not found"),
+			want: "user not found\n",
+		},
+		"internal": {
+			err:  xerrors.New("sql: connection refused"),
+			want: xerrors.DefaultPublicMessage + "\n",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			Error(rec, test.err, http.StatusNotFound)
+
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+			assert.Equal(t, test.want, rec.Body.String())
+		})
+	}
+}
+
+func TestErrorReport(t *testing.T) {
+	var mu sync.Mutex
+	reported := map[error]bool{}
+	xerrors.OnReport(func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		reported[err] = true
+	})
+
+	for name, test := range map[string]struct {
+		err          error
+		code         int
+		wantReported bool
+	}{
+		"server_error": {
+			err:          xerrors.New("sql: connection refused"),
+			code:         http.StatusInternalServerError,
+			wantReported: true,
+		},
+		"client_error": {
+			err:  xerrors.New("invalid id"),
+			code: http.StatusBadRequest,
+		},
+		"expected": {
+			err:          xerrors.Expected(xerrors.New("upstream overloaded")),
+			code:         http.StatusServiceUnavailable,
+			wantReported: true,
+		},
+		"canceled": {
+			err:          xerrors.Join(context.Canceled, "request aborted"),
+			code:         http.StatusInternalServerError,
+			wantReported: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			Error(httptest.NewRecorder(), test.err, test.code)
+
+			mu.Lock()
+			defer mu.Unlock()
+			assert.Equal(t, test.wantReported, reported[test.err])
+		})
+	}
+}
+
+func TestErrorExpectedTotal(t *testing.T) {
+	r := xmetrics.NewRegistry(nil)
+	t.Cleanup(xmetrics.Install(r))
+
+	err := xerrors.Expected(xerrors.New("upstream overloaded"))
+	Error(httptest.NewRecorder(), err, http.StatusServiceUnavailable)
+
+	info := xerrors.Info(err)
+	assert.EqualValues(t, 1, r.Counter(xmetrics.ExpectedTotal, "").Value(info.Type, info.Fingerprint))
+	assert.EqualValues(t, 0, r.Counter(xmetrics.ReportedTotal, "").Value(info.Type, info.Fingerprint))
+}
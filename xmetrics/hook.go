@@ -0,0 +1,88 @@
+package xmetrics
+
+import (
+	"sync"
+
+	"github.com/emilien-puget/xerrors"
+)
+
+const (
+	// CreatedTotal is the name of the counter of the errors created.
+	CreatedTotal = "xerrors_created_total"
+	// ReportedTotal is the name of the counter of the errors passed to [xerrors.Report].
+	ReportedTotal = "xerrors_reported_total"
+	// ExpectedTotal is the name of the counter of the expected errors passed to [xerrors.Report].
+	ExpectedTotal = "xerrors_expected_total"
+)
+
+// counters are the counters of an installed registry.
+type counters struct {
+	created  *Counter
+	reported *Counter
+	expected *Counter
+}
+
+var (
+	// hooksOnce registers the hooks of this package, they count the errors in every installed registry.
+	hooksOnce   sync.Once
+	installedMu sync.RWMutex
+	installed   = map[*Registry]counters{}
+)
+
+// Install counts the errors created by New and Join and the errors reported in r,
+// partitioned by type and fingerprint, until the returned function is called.
+// Expected errors, see [xerrors.IsExpected], are excluded from the error counters and reported in their own counter.
+// Installing a registry more than once has no effect.
+func Install(r *Registry) (uninstall func()) {
+	hooksOnce.Do(func() {
+		xerrors.OnCreateKind(xerrors.CreatedByNew, countCreated)
+		xerrors.OnCreateKind(xerrors.CreatedByJoin, countCreated)
+		xerrors.OnReport(countReported)
+	})
+
+	installedMu.Lock()
+	defer installedMu.Unlock()
+	if _, ok := installed[r]; !ok {
+		installed[r] = counters{
+			created:  r.Counter(CreatedTotal, "Number of errors created.", "type", "fingerprint"),
+			reported: r.Counter(ReportedTotal, "Number of errors reported.", "type", "fingerprint"),
+			expected: r.Counter(ExpectedTotal, "Number of expected errors reported.", "type", "fingerprint"),
+		}
+	}
+
+	return func() {
+		installedMu.Lock()
+		defer installedMu.Unlock()
+		delete(installed, r)
+	}
+}
+
+func countCreated(err error) {
+	if xerrors.IsExpected(err) {
+		return
+	}
+	count(err, func(c counters) *Counter { return c.created })
+}
+
+func countReported(err error) {
+	if xerrors.IsExpected(err) {
+		count(err, func(c counters) *Counter { return c.expected })
+		return
+	}
+	count(err, func(c counters) *Counter { return c.reported })
+}
+
+// count increments the counter of every installed registry selected by counter, the type and the fingerprint of err
+// being computed only if a registry is installed.
+func count(err error, counter func(c counters) *Counter) {
+	installedMu.RLock()
+	defer installedMu.RUnlock()
+	if len(installed) == 0 {
+		return
+	}
+
+	typ, fingerprint := xerrors.Type(err), xerrors.Fingerprint(err)
+	for _, c := range installed {
+		counter(c).Inc(typ, fingerprint)
+	}
+}
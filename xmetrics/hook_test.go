@@ -0,0 +1,58 @@
+package xmetrics
+
+import (
+	"testing"
+
+	"github.com/emilien-puget/xerrors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstall(t *testing.T) {
+	r := NewRegistry(nil)
+	t.Cleanup(Install(r))
+
+	err := xerrors.New("error")
+	xerrors.Report(err)
+
+	info := xerrors.Info(err)
+	assert.EqualValues(t, 1, r.Counter(CreatedTotal, "").Value(info.Type, info.Fingerprint))
+	assert.EqualValues(t, 1, r.Counter(ReportedTotal, "").Value(info.Type, info.Fingerprint))
+}
+
+func TestInstallExpected(t *testing.T) {
+	r := NewRegistry(nil)
+	t.Cleanup(Install(r))
+
+	err := xerrors.Join(xerrors.Expected(xerrors.New("user not found")), "loading user")
+	xerrors.Report(err)
+
+	info := xerrors.Info(err)
+	assert.EqualValues(t, 0, r.Counter(CreatedTotal, "").Value(info.Type, info.Fingerprint))
+	assert.EqualValues(t, 0, r.Counter(ReportedTotal, "").Value(info.Type, info.Fingerprint))
+	assert.EqualValues(t, 1, r.Counter(ExpectedTotal, "").Value(info.Type, info.Fingerprint))
+}
+
+func TestInstallTwice(t *testing.T) {
+	r := NewRegistry(nil)
+	t.Cleanup(Install(r))
+	t.Cleanup(Install(r))
+
+	err := xerrors.New("error")
+	xerrors.Report(err)
+
+	info := xerrors.Info(err)
+	assert.EqualValues(t, 1, r.Counter(ReportedTotal, "").Value(info.Type, info.Fingerprint))
+}
+
+func TestUninstall(t *testing.T) {
+	r := NewRegistry(nil)
+	uninstall := Install(r)
+	uninstall()
+
+	err := xerrors.New("error")
+	xerrors.Report(err)
+
+	info := xerrors.Info(err)
+	assert.EqualValues(t, 0, r.Counter(CreatedTotal, "").Value(info.Type, info.Fingerprint))
+	assert.EqualValues(t, 0, r.Counter(ReportedTotal, "").Value(info.Type, info.Fingerprint))
+}
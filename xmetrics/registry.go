@@ -0,0 +1,189 @@
+// Package xmetrics counts errors created with xerrors and exposes the counters in the Prometheus text exposition format.
+package xmetrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// OverflowValue is the label value of the series counting the increments that exceeded the series limit of a counter.
+const OverflowValue = "__overflow__"
+
+// Options configures a Registry.
+type Options struct {
+	// MaxSeries is the maximum number of label sets of a counter, 1000 if zero.
+	// The increments of new label sets beyond the limit are counted in a series whose labels are all OverflowValue.
+	MaxSeries int
+}
+
+// Registry holds counters and exposes them in the Prometheus text exposition format.
+// A Registry is safe for concurrent use.
+type Registry struct {
+	mu        sync.Mutex
+	counters  []*Counter
+	maxSeries int
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry(opts *Options) *Registry {
+	if opts == nil {
+		opts = &Options{}
+	}
+	r := &Registry{
+		maxSeries: opts.MaxSeries,
+	}
+	if r.maxSeries == 0 {
+		r.maxSeries = 1000
+	}
+	return r
+}
+
+// Counter returns the counter named name, it is created with help and labels if it does not exist yet.
+func (r *Registry) Counter(name, help string, labels ...string) *Counter {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, c := range r.counters {
+		if c.name == name {
+			return c
+		}
+	}
+
+	c := &Counter{
+		name:      name,
+		help:      help,
+		labels:    labels,
+		maxSeries: r.maxSeries,
+		series:    make(map[string]*series),
+	}
+	r.counters = append(r.counters, c)
+	return c
+}
+
+// ServeHTTP implements [http.Handler], it writes the counters in the Prometheus text exposition format.
+func (r *Registry) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_ = r.Write(w)
+}
+
+// Write writes the counters to w in the Prometheus text exposition format.
+func (r *Registry) Write(w io.Writer) error {
+	r.mu.Lock()
+	counters := make([]*Counter, len(r.counters))
+	copy(counters, r.counters)
+	r.mu.Unlock()
+
+	sort.Slice(counters, func(i, j int) bool {
+		return counters[i].name < counters[j].name
+	})
+
+	bw := bufio.NewWriter(w)
+	for _, c := range counters {
+		c.write(bw)
+	}
+	return bw.Flush()
+}
+
+// Counter is a monotonic counter partitioned by labels.
+type Counter struct {
+	name      string
+	help      string
+	labels    []string
+	maxSeries int
+
+	mu     sync.Mutex
+	series map[string]*series
+}
+
+type series struct {
+	values []string
+	count  uint64
+}
+
+// Inc increments the series of the counter identified by values, given in the order of the labels of the counter.
+func (c *Counter) Inc(values ...string) {
+	if len(values) != len(c.labels) {
+		panic(fmt.Sprintf("xmetrics: counter %s has %d labels, got %d values", c.name, len(c.labels), len(values)))
+	}
+	key := strings.Join(values, "\xff")
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s, ok := c.series[key]
+	if !ok {
+		if len(c.series) >= c.maxSeries {
+			s = c.overflow()
+		} else {
+			s = &series{values: append([]string(nil), values...)}
+			c.series[key] = s
+		}
+	}
+	s.count++
+}
+
+// overflow returns the series counting the increments beyond the series limit, c.mu must be held.
+func (c *Counter) overflow() *series {
+	values := make([]string, len(c.labels))
+	for i := range values {
+		values[i] = OverflowValue
+	}
+	key := strings.Join(values, "\xff")
+	s, ok := c.series[key]
+	if !ok {
+		s = &series{values: values}
+		c.series[key] = s
+	}
+	return s
+}
+
+// Value returns the value of the series identified by values.
+func (c *Counter) Value(values ...string) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s, ok := c.series[strings.Join(values, "\xff")]
+	if !ok {
+		return 0
+	}
+	return s.count
+}
+
+func (c *Counter) write(w *bufio.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	keys := make([]string, 0, len(c.series))
+	for k := range c.series {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	_, _ = fmt.Fprintf(w, "# HELP %s %s\n", c.name, helpEscaper.Replace(c.help))
+	_, _ = fmt.Fprintf(w, "# TYPE %s counter\n", c.name)
+	for _, k := range keys {
+		s := c.series[k]
+		_, _ = w.WriteString(c.name)
+		if len(c.labels) > 0 {
+			_ = w.WriteByte('{')
+			for i, label := range c.labels {
+				if i > 0 {
+					_ = w.WriteByte(',')
+				}
+				_, _ = fmt.Fprintf(w, "%s=\"%s\"", label, labelEscaper.Replace(s.values[i]))
+			}
+			_ = w.WriteByte('}')
+		}
+		_, _ = fmt.Fprintf(w, " %d\n", s.count)
+	}
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
@@ -0,0 +1,86 @@
+package xmetrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry(nil)
+	c := r.Counter("errors_total", "Number of errors.\nWith a new line.", "type", "code")
+	assert.Same(t, c, r.Counter("errors_total", "ignored"))
+
+	c.Inc("*net.OpError", "500")
+	c.Inc("*net.OpError", "500")
+	c.Inc(`a "quoted\type`, "404")
+	r.Counter("another_total", "Another counter.").Inc()
+
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+	resp, err := http.Get(srv.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", resp.Header.Get("Content-Type"))
+	assert.Equal(t, `# HELP another_total Another counter.
+# TYPE another_total counter
+another_total 1
+# HELP errors_total Number of errors.\nWith a new line.
+# TYPE errors_total counter
+errors_total{type="*net.OpError",code="500"} 2
+errors_total{type="a \"quoted\\type",code="404"} 1
+`, string(body))
+}
+
+func TestCounterMaxSeries(t *testing.T) {
+	r := NewRegistry(&Options{MaxSeries: 2})
+	c := r.Counter("errors_total", "Number of errors.", "type")
+
+	c.Inc("a")
+	c.Inc("b")
+	c.Inc("c")
+	c.Inc("d")
+	c.Inc("a")
+
+	assert.EqualValues(t, 2, c.Value("a"))
+	assert.EqualValues(t, 1, c.Value("b"))
+	assert.EqualValues(t, 0, c.Value("c"))
+	assert.EqualValues(t, 2, c.Value(OverflowValue))
+}
+
+func TestCounterLabelsMismatch(t *testing.T) {
+	c := NewRegistry(nil).Counter("errors_total", "Number of errors.", "type")
+	assert.Panics(t, func() {
+		c.Inc("a", "b")
+	})
+}
+
+func TestCounterConcurrent(t *testing.T) {
+	r := NewRegistry(nil)
+	c := r.Counter("errors_total", "Number of errors.", "type")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				c.Inc("a")
+			}
+			var sb strings.Builder
+			_ = r.Write(&sb)
+		}()
+	}
+	wg.Wait()
+
+	assert.EqualValues(t, 1000, c.Value("a"))
+}
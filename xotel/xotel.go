@@ -0,0 +1,135 @@
+// Package xotel records errors created with xerrors on OpenTelemetry spans.
+package xotel
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/emilien-puget/xerrors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// TraceIDKey is the key of the value holding the trace id attached by WithContext.
+	TraceIDKey = "trace_id"
+	// SpanIDKey is the key of the value holding the span id attached by WithContext.
+	SpanIDKey = "span_id"
+)
+
+// RecordError records err on span.
+// It sets the status of the span to error, adds an exception event built from [xerrors.Info]
+// and maps the values of the error to attributes of the span.
+func RecordError(span trace.Span, err error) {
+	if err == nil || !span.IsRecording() {
+		return
+	}
+
+	info := xerrors.Info(err)
+
+	span.SetStatus(codes.Error, info.ErrorChain)
+	span.AddEvent(semconv.ExceptionEventName, trace.WithAttributes(
+		semconv.ExceptionType(info.Type),
+		semconv.ExceptionMessage(info.ErrorChain),
+		semconv.ExceptionStacktrace(strings.Join(info.StackTraces, "\n")),
+	))
+	span.SetAttributes(Attributes(info.Values)...)
+}
+
+// Attributes converts values to span attributes, sorted by key.
+// The type of the attribute follows the type of the value, values that have no attribute counterpart are stringified.
+func Attributes(values map[string]any) []attribute.KeyValue {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	attrs := make([]attribute.KeyValue, 0, len(keys))
+	for _, k := range keys {
+		attrs = append(attrs, attributeOf(k, values[k]))
+	}
+	return attrs
+}
+
+func attributeOf(key string, v any) attribute.KeyValue {
+	switch t := v.(type) {
+	case string:
+		return attribute.String(key, t)
+	case bool:
+		return attribute.Bool(key, t)
+	case int:
+		return attribute.Int(key, t)
+	case int8:
+		return attribute.Int64(key, int64(t))
+	case int16:
+		return attribute.Int64(key, int64(t))
+	case int32:
+		return attribute.Int64(key, int64(t))
+	case int64:
+		return attribute.Int64(key, t)
+	case uint8:
+		return attribute.Int64(key, int64(t))
+	case uint16:
+		return attribute.Int64(key, int64(t))
+	case uint32:
+		return attribute.Int64(key, int64(t))
+	case uint:
+		return uintAttribute(key, uint64(t))
+	case uint64:
+		return uintAttribute(key, t)
+	case float32:
+		return attribute.Float64(key, float64(t))
+	case float64:
+		return attribute.Float64(key, t)
+	case []string:
+		return attribute.StringSlice(key, t)
+	case []bool:
+		return attribute.BoolSlice(key, t)
+	case []int:
+		return attribute.IntSlice(key, t)
+	case []int64:
+		return attribute.Int64Slice(key, t)
+	case []float64:
+		return attribute.Float64Slice(key, t)
+	case error:
+		return attribute.String(key, t.Error())
+	case fmt.Stringer:
+		return attribute.String(key, t.String())
+	default:
+		return attribute.String(key, fmt.Sprint(v))
+	}
+}
+
+// uintAttribute returns an int64 attribute when v fits in it, a string attribute otherwise.
+func uintAttribute(key string, v uint64) attribute.KeyValue {
+	if v > math.MaxInt64 {
+		return attribute.String(key, strconv.FormatUint(v, 10))
+	}
+	return attribute.Int64(key, int64(v))
+}
+
+// WithContext returns err joined with the trace id and the span id of the span found in ctx,
+// so that logs of the error can be correlated with the trace.
+// err is returned as is when ctx does not hold a valid span context.
+func WithContext(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return err
+	}
+
+	return xerrors.Join(err, xerrors.WithValues(map[string]any{
+		TraceIDKey: sc.TraceID().String(),
+		SpanIDKey:  sc.SpanID().String(),
+	}))
+}
@@ -0,0 +1,158 @@
+package xotel
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/emilien-puget/xerrors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+func newTracer(t *testing.T) (*sdktrace.TracerProvider, *tracetest.InMemoryExporter) {
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	t.Cleanup(func() {
+		_ = tp.Shutdown(context.Background())
+	})
+	return tp, exporter
+}
+
+func TestRecordError(t *testing.T) {
+	tp, exporter := newTracer(t)
+	_, span := tp.Tracer("test").Start(context.Background(), "op")
+
+	err := xerrors.New("error")
+	err = xerrors.Join(err, "its a wrap", xerrors.WithValues(map[string]any{
+		"str":   "bar",
+		"int":   42,
+		"float": 4.2,
+		"bool":  true,
+		"slice": []string{"a", "b"},
+		"other": struct{ A int }{A: 1},
+	}))
+	RecordError(span, err)
+	span.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	s := spans[0]
+
+	assert.Equal(t, codes.Error, s.Status.Code)
+	assert.Equal(t, "error: its a wrap", s.Status.Description)
+
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.Bool("bool", true),
+		attribute.Float64("float", 4.2),
+		attribute.Int("int", 42),
+		attribute.String("other", "{1}"),
+		attribute.StringSlice("slice", []string{"a", "b"}),
+		attribute.String("str", "bar"),
+	}, s.Attributes)
+
+	require.Len(t, s.Events, 1)
+	event := s.Events[0]
+	assert.Equal(t, semconv.ExceptionEventName, event.Name)
+	attrs := attribute.NewSet(event.Attributes...)
+	typ, _ := attrs.Value(semconv.ExceptionTypeKey)
+	assert.Equal(t, "*xerrors.errorString", typ.AsString())
+	msg, _ := attrs.Value(semconv.ExceptionMessageKey)
+	assert.Equal(t, "error: its a wrap", msg.AsString())
+	st, _ := attrs.Value(semconv.ExceptionStacktraceKey)
+	assert.Contains(t, st.AsString(), "github.com/emilien-puget/xerrors/xotel.TestRecordError")
+}
+
+func TestRecordErrorForeign(t *testing.T) {
+	tp, exporter := newTracer(t)
+	_, span := tp.Tracer("test").Start(context.Background(), "op")
+
+	RecordError(span, errors.New("plouf"))
+	span.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	s := spans[0]
+	assert.Equal(t, codes.Error, s.Status.Code)
+	assert.Equal(t, "plouf", s.Status.Description)
+
+	require.Len(t, s.Events, 1)
+	attrs := attribute.NewSet(s.Events[0].Attributes...)
+	typ, _ := attrs.Value(semconv.ExceptionTypeKey)
+	assert.Equal(t, "*errors.errorString", typ.AsString())
+	msg, _ := attrs.Value(semconv.ExceptionMessageKey)
+	assert.Equal(t, "plouf", msg.AsString())
+}
+
+func TestRecordErrorNil(t *testing.T) {
+	tp, exporter := newTracer(t)
+	_, span := tp.Tracer("test").Start(context.Background(), "op")
+
+	RecordError(span, nil)
+	span.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Unset, spans[0].Status.Code)
+	assert.Empty(t, spans[0].Events)
+}
+
+func TestWithContext(t *testing.T) {
+	tp, _ := newTracer(t)
+	ctx, span := tp.Tracer("test").Start(context.Background(), "op")
+	defer span.End()
+
+	err := WithContext(ctx, xerrors.New("error"))
+
+	sc := span.SpanContext()
+	assert.Equal(t, map[string]any{
+		TraceIDKey: sc.TraceID().String(),
+		SpanIDKey:  sc.SpanID().String(),
+	}, xerrors.Values(err))
+}
+
+func TestWithContextNoSpan(t *testing.T) {
+	errmy := errors.New("plouf")
+	assert.Same(t, errmy, WithContext(context.Background(), errmy))
+	assert.NoError(t, WithContext(context.Background(), nil))
+}
+
+func TestAttributes(t *testing.T) {
+	tests := map[string]struct {
+		value any
+		want  attribute.KeyValue
+	}{
+		"uint8": {
+			value: uint8(8),
+			want:  attribute.Int64("k", 8),
+		},
+		"uint32": {
+			value: uint32(32),
+			want:  attribute.Int64("k", 32),
+		},
+		"uint": {
+			value: uint(42),
+			want:  attribute.Int64("k", 42),
+		},
+		"uint64": {
+			value: uint64(64),
+			want:  attribute.Int64("k", 64),
+		},
+		"uint64_overflow": {
+			value: uint64(math.MaxUint64),
+			want:  attribute.String("k", "18446744073709551615"),
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, []attribute.KeyValue{test.want}, Attributes(map[string]any{"k": test.value}))
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package xsentry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/emilien-puget/xerrors"
+)
+
+// ClientOptions configures a Client.
+type ClientOptions struct {
+	EventOptions
+	// HTTPClient is the client used to post the events, [http.DefaultClient] if nil.
+	HTTPClient *http.Client
+}
+
+// Client sends events to a Sentry compatible ingest.
+type Client struct {
+	endpoint   string
+	auth       string
+	httpClient *http.Client
+	eventOpts  EventOptions
+}
+
+// NewClient returns a Client that sends the events to the project identified by dsn,
+// formatted as "{scheme}://{public_key}@{host}/{path}{project_id}".
+func NewClient(dsn string, opts *ClientOptions) (*Client, error) {
+	if opts == nil {
+		opts = &ClientOptions{}
+	}
+
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, xerrors.Join(err, "parsing dsn")
+	}
+	if u.User == nil || u.User.Username() == "" {
+		return nil, xerrors.New("dsn has no public key")
+	}
+	path, projectID := "", strings.TrimPrefix(u.Path, "/")
+	if i := strings.LastIndex(projectID, "/"); i >= 0 {
+		path, projectID = projectID[:i+1], projectID[i+1:]
+	}
+	if projectID == "" {
+		return nil, xerrors.New("dsn has no project id")
+	}
+
+	httpClient := opts.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return &Client{
+		endpoint:   fmt.Sprintf("%s://%s/%sapi/%s/store/", u.Scheme, u.Host, path, projectID),
+		auth:       fmt.Sprintf("Sentry sentry_version=7, sentry_client=xsentry/1.0, sentry_key=%s", u.User.Username()),
+		httpClient: httpClient,
+		eventOpts:  opts.EventOptions,
+	}, nil
+}
+
+// Capture converts err into an Event and sends it, it returns the id of the event.
+// Nothing is sent if err is nil.
+func (c *Client) Capture(ctx context.Context, err error) (string, error) {
+	if err == nil {
+		return "", nil
+	}
+	event := NewEvent(err, &c.eventOpts)
+	return event.EventID, c.Send(ctx, event)
+}
+
+// Send posts event to the ingest.
+func (c *Client) Send(ctx context.Context, event *Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return xerrors.Join(err, "encoding event")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return xerrors.Join(err, "creating request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Sentry-Auth", c.auth)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return xerrors.Join(err, "sending event")
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return xerrors.New("unexpected status " + resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package xsentry
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/emilien-puget/xerrors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientCapture(t *testing.T) {
+	var (
+		gotPath  string
+		gotAuth  string
+		gotEvent Event
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotAuth = r.Header.Get("X-Sentry-Auth")
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&gotEvent))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	dsn := strings.Replace(srv.URL, "http://", "http://public@", 1) + "/sentry/42"
+	client, err := NewClient(dsn, &ClientOptions{HTTPClient: srv.Client()})
+	require.NoError(t, err)
+
+	id, err := client.Capture(context.Background(), xerrors.Join(xerrors.New("error"), "its a wrap"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "/sentry/api/42/store/", gotPath)
+	assert.Equal(t, "Sentry sentry_version=7, sentry_client=xsentry/1.0, sentry_key=public", gotAuth)
+	assert.Equal(t, id, gotEvent.EventID)
+	require.Len(t, gotEvent.Exception.Values, 1)
+	assert.Equal(t, "error: its a wrap", gotEvent.Exception.Values[0].Value)
+}
+
+func TestClientCaptureStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	client, err := NewClient(strings.Replace(srv.URL, "http://", "http://public@", 1)+"/1", nil)
+	require.NoError(t, err)
+
+	_, err = client.Capture(context.Background(), xerrors.New("error"))
+	assert.EqualError(t, err, "unexpected status 429 Too Many Requests")
+}
+
+func TestClientCaptureNil(t *testing.T) {
+	called := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer srv.Close()
+
+	client, err := NewClient(strings.Replace(srv.URL, "http://", "http://public@", 1)+"/1", nil)
+	require.NoError(t, err)
+
+	id, err := client.Capture(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Empty(t, id)
+	assert.False(t, called)
+}
+
+func TestNewClientInvalidDSN(t *testing.T) {
+	for name, dsn := range map[string]string{
+		"no_key":     "https://sentry.example.com/1",
+		"no_project": "https://public@sentry.example.com/",
+		"invalid":    "://",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewClient(dsn, nil)
+			assert.Error(t, err)
+		})
+	}
+}
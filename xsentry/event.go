@@ -0,0 +1,177 @@
+// Package xsentry converts errors created with xerrors into Sentry events and sends them to a Sentry compatible ingest.
+package xsentry
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/emilien-puget/xerrors"
+)
+
+// Event is a Sentry event.
+type Event struct {
+	EventID     string            `json:"event_id"`
+	Timestamp   time.Time         `json:"timestamp"`
+	Platform    string            `json:"platform"`
+	Level       string            `json:"level"`
+	Environment string            `json:"environment,omitempty"`
+	Release     string            `json:"release,omitempty"`
+	Exception   ExceptionList     `json:"exception"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	Fingerprint []string          `json:"fingerprint,omitempty"`
+}
+
+// ExceptionList holds the exceptions of an Event, the last one being the main exception.
+type ExceptionList struct {
+	Values []Exception `json:"values"`
+}
+
+// Exception is an error of an Event.
+type Exception struct {
+	Type       string      `json:"type"`
+	Value      string      `json:"value"`
+	Stacktrace *Stacktrace `json:"stacktrace,omitempty"`
+}
+
+// Stacktrace holds the frames of an Exception, the most recent call being the last one.
+type Stacktrace struct {
+	Frames []Frame `json:"frames"`
+}
+
+// Frame is a frame of a Stacktrace.
+type Frame struct {
+	Function string `json:"function"`
+	Module   string `json:"module"`
+	Filename string `json:"filename"`
+	AbsPath  string `json:"abs_path"`
+	Lineno   int    `json:"lineno"`
+	InApp    bool   `json:"in_app"`
+}
+
+// EventOptions configures the conversion of an error into an Event.
+type EventOptions struct {
+	Environment string
+	Release     string
+	// InAppPrefixes are the prefixes of the packages considered part of the application.
+	// Every package outside the standard library is considered part of the application if empty.
+	InAppPrefixes []string
+}
+
+// NewEvent converts err into an Event, it returns nil if err is nil.
+// The Join tree of err is walked and an exception is added for every joined error, with its own stack trace when it
+// has one, see [xerrors.StackFrames]; the main exception holds the whole error chain as value.
+// The level of the event is derived from [xerrors.Severity], expected errors being reported at the info level.
+func NewEvent(err error, opts *EventOptions) *Event {
+	if err == nil {
+		return nil
+	}
+	if opts == nil {
+		opts = &EventOptions{}
+	}
+
+	exceptions := []Exception{newException(err, opts.InAppPrefixes)}
+	var walk func(err error)
+	walk = func(err error) {
+		for _, joined := range xerrors.JoinedErrors(err) {
+			exceptions = append(exceptions, newException(joined, opts.InAppPrefixes))
+			walk(joined)
+		}
+	}
+	walk(err)
+	// Sentry expects the main exception last.
+	for i, j := 0, len(exceptions)-1; i < j; i, j = i+1, j-1 {
+		exceptions[i], exceptions[j] = exceptions[j], exceptions[i]
+	}
+
+	var tags map[string]string
+	if values := xerrors.Values(err); len(values) > 0 {
+		tags = make(map[string]string, len(values))
+		for k, v := range values {
+			tags[k] = fmt.Sprint(v)
+		}
+	}
+
+	return &Event{
+		EventID:     newEventID(),
+		Timestamp:   time.Now().UTC(),
+		Platform:    "go",
+		Level:       level(xerrors.Severity(err)),
+		Environment: opts.Environment,
+		Release:     opts.Release,
+		Exception:   ExceptionList{Values: exceptions},
+		Tags:        tags,
+		Fingerprint: []string{xerrors.Fingerprint(err)},
+	}
+}
+
+// level returns the Sentry level matching a slog level.
+func level(l slog.Level) string {
+	switch {
+	case l < slog.LevelInfo:
+		return "debug"
+	case l < slog.LevelWarn:
+		return "info"
+	case l < slog.LevelError:
+		return "warning"
+	case l < slog.LevelError+4:
+		return "error"
+	default:
+		return "fatal"
+	}
+}
+
+func newException(err error, inAppPrefixes []string) Exception {
+	exception := Exception{
+		Type:  xerrors.Info(err).Type,
+		Value: err.Error(),
+	}
+	if exception.Type == "" {
+		exception.Type = fmt.Sprintf("%T", err)
+	}
+	if frames := xerrors.StackFrames(err); frames != nil {
+		exception.Stacktrace = newStacktrace(frames.Frames(), inAppPrefixes)
+	}
+	return exception
+}
+
+func newStacktrace(frames []xerrors.Frame, inAppPrefixes []string) *Stacktrace {
+	st := &Stacktrace{
+		Frames: make([]Frame, 0, len(frames)),
+	}
+	// Sentry expects the most recent call last.
+	for i := len(frames) - 1; i >= 0; i-- {
+		st.Frames = append(st.Frames, Frame{
+			Function: frames[i].Name(),
+			Module:   frames[i].Package(),
+			Filename: filepath.Base(frames[i].File),
+			AbsPath:  frames[i].File,
+			Lineno:   frames[i].Line,
+			InApp:    inApp(frames[i], inAppPrefixes),
+		})
+	}
+	return st
+}
+
+func inApp(frame xerrors.Frame, inAppPrefixes []string) bool {
+	if len(inAppPrefixes) == 0 {
+		return !frame.StandardLibrary()
+	}
+	module := frame.Package()
+	for _, prefix := range inAppPrefixes {
+		if strings.HasPrefix(module, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func newEventID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,131 @@
+package xsentry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/emilien-puget/xerrors"
+	pkgerrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEvent(t *testing.T) {
+	err := xerrors.New("error")
+	err = xerrors.Join(err, "its a wrap", xerrors.New("secondary"), xerrors.WithValues(map[string]any{"user": 42}))
+
+	event := NewEvent(err, &EventOptions{Environment: "test", Release: "1.0.0"})
+
+	assert.Len(t, event.EventID, 32)
+	assert.Equal(t, "go", event.Platform)
+	assert.Equal(t, "error", event.Level)
+	assert.Equal(t, "test", event.Environment)
+	assert.Equal(t, "1.0.0", event.Release)
+	assert.Equal(t, map[string]string{"user": "42"}, event.Tags)
+	assert.Equal(t, []string{xerrors.Fingerprint(err)}, event.Fingerprint)
+
+	exceptions := event.Exception.Values
+	require.Len(t, exceptions, 2)
+	assert.Equal(t, "*xerrors.errorString", exceptions[0].Type)
+	assert.Equal(t, "secondary", exceptions[0].Value)
+	main := exceptions[1]
+	assert.Equal(t, "*xerrors.errorString", main.Type)
+	assert.Equal(t, "error: its a wrap + secondary", main.Value)
+
+	require.NotNil(t, main.Stacktrace)
+	frames := main.Stacktrace.Frames
+	require.Len(t, frames, 3)
+	last := frames[len(frames)-1]
+	assert.Equal(t, "TestNewEvent", last.Function)
+	assert.Equal(t, "github.com/emilien-puget/xerrors/xsentry", last.Module)
+	assert.Equal(t, "event_test.go", last.Filename)
+	assert.Equal(t, 17, last.Lineno)
+	assert.True(t, last.InApp)
+	assert.Equal(t, "runtime", frames[0].Module)
+	assert.False(t, frames[0].InApp)
+}
+
+func TestNewEventForeign(t *testing.T) {
+	event := NewEvent(errors.New("plouf"), nil)
+
+	assert.Equal(t, []Exception{{Type: "*errors.errorString", Value: "plouf"}}, event.Exception.Values)
+	assert.Nil(t, event.Tags)
+}
+
+func TestNewEventJoinTree(t *testing.T) {
+	err := xerrors.Join(xerrors.New("error"), "its a wrap",
+		io.EOF,
+		pkgerrors.New("pkg"),
+		xerrors.Join(xerrors.New("nested"), context.Canceled),
+	)
+
+	exceptions := NewEvent(err, nil).Exception.Values
+	require.Len(t, exceptions, 5)
+
+	assert.Equal(t, "*errors.errorString", exceptions[0].Type)
+	assert.Equal(t, "context canceled", exceptions[0].Value)
+	assert.Nil(t, exceptions[0].Stacktrace)
+
+	assert.Equal(t, "nested: context canceled", exceptions[1].Value)
+	require.NotNil(t, exceptions[1].Stacktrace)
+
+	assert.Equal(t, "*errors.fundamental", exceptions[2].Type)
+	assert.Equal(t, "pkg", exceptions[2].Value)
+	require.NotNil(t, exceptions[2].Stacktrace, "the pkg/errors stack traces are kept")
+	frames := exceptions[2].Stacktrace.Frames
+	assert.Equal(t, "TestNewEventJoinTree", frames[len(frames)-1].Function)
+
+	assert.Equal(t, "*errors.errorString", exceptions[3].Type)
+	assert.Equal(t, "EOF", exceptions[3].Value)
+	assert.Nil(t, exceptions[3].Stacktrace, "the joined errors without a stack trace are kept")
+
+	assert.Equal(t, err.Error(), exceptions[4].Value)
+}
+
+func TestNewEventNil(t *testing.T) {
+	assert.Nil(t, NewEvent(nil, nil))
+}
+
+func TestNewEventLevel(t *testing.T) {
+	tests := map[string]struct {
+		err  error
+		want string
+	}{
+		"default": {
+			err:  xerrors.New("error"),
+			want: "error",
+		},
+		"expected": {
+			err:  xerrors.Expected(xerrors.New("error")),
+			want: "info",
+		},
+		"debug": {
+			err:  xerrors.WithSeverity(xerrors.New("error"), slog.LevelDebug),
+			want: "debug",
+		},
+		"warning": {
+			err:  xerrors.WithSeverity(xerrors.New("error"), slog.LevelWarn),
+			want: "warning",
+		},
+		"fatal": {
+			err:  xerrors.WithSeverity(xerrors.New("error"), slog.LevelError+4),
+			want: "fatal",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.want, NewEvent(test.err, nil).Level)
+		})
+	}
+}
+
+func TestNewEventInAppPrefixes(t *testing.T) {
+	event := NewEvent(xerrors.New("error"), &EventOptions{InAppPrefixes: []string{"testing"}})
+
+	frames := event.Exception.Values[0].Stacktrace.Frames
+	assert.False(t, frames[len(frames)-1].InApp)
+	assert.True(t, frames[1].InApp)
+}
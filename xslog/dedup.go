@@ -0,0 +1,249 @@
+package xslog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/emilien-puget/xerrors"
+)
+
+// DedupOptions configures a DedupHandler.
+type DedupOptions struct {
+	// Window is the duration during which the repeats of an error are suppressed, 10 seconds if zero.
+	Window time.Duration
+	// Now returns the current time, [time.Now] if nil.
+	Now func() time.Time
+	// FlushInterval is the interval at which the summaries of the windows that are over are emitted
+	// and the errors that are not repeated anymore are forgotten, the window if zero.
+	// A negative interval disables the periodic flush, Flush must then be called by the user.
+	FlushInterval time.Duration
+}
+
+// DedupHandler wraps a [slog.Handler] and suppresses the records holding an error whose fingerprint
+// was already logged within the window. The first occurrence is logged as is, the repeats are counted
+// and a summary holding the first occurrence is emitted once the window is over.
+//
+// Summaries are emitted when an error occurs again after its window is over, and by Flush,
+// which is called periodically in the background unless disabled by DedupOptions.FlushInterval.
+// Close stops the periodic flush. A DedupHandler is safe for concurrent use.
+type DedupHandler struct {
+	handler slog.Handler
+	window  time.Duration
+	now     func() time.Time
+	state   *dedupState
+}
+
+type dedupState struct {
+	mu      sync.Mutex
+	entries map[string]*dedupEntry
+
+	closeOnce sync.Once
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+type dedupEntry struct {
+	err     error
+	level   slog.Level
+	handler slog.Handler
+	start   time.Time
+	count   int
+}
+
+// NewDedupHandler returns a DedupHandler that wraps h.
+func NewDedupHandler(h slog.Handler, opts *DedupOptions) *DedupHandler {
+	if opts == nil {
+		opts = &DedupOptions{}
+	}
+	d := &DedupHandler{
+		handler: h,
+		window:  opts.Window,
+		now:     opts.Now,
+		state: &dedupState{
+			entries: make(map[string]*dedupEntry),
+			stop:    make(chan struct{}),
+			done:    make(chan struct{}),
+		},
+	}
+	if d.window == 0 {
+		d.window = 10 * time.Second
+	}
+	if d.now == nil {
+		d.now = time.Now
+	}
+
+	interval := opts.FlushInterval
+	if interval == 0 {
+		interval = d.window
+	}
+	if interval > 0 {
+		go d.flushEvery(interval)
+	} else {
+		close(d.state.done)
+	}
+	return d
+}
+
+// flushEvery calls Flush every interval until the handler is closed.
+func (d *DedupHandler) flushEvery(interval time.Duration) {
+	defer close(d.state.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			// there is no caller to report the error to, as with a failing Handle called by a slog.Logger.
+			_ = d.Flush(context.Background())
+		case <-d.state.stop:
+			return
+		}
+	}
+}
+
+// Enabled implements [slog.Handler].
+func (d *DedupHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return d.handler.Enabled(ctx, level)
+}
+
+// Handle implements [slog.Handler].
+func (d *DedupHandler) Handle(ctx context.Context, r slog.Record) error {
+	var err error
+	r.Attrs(func(a slog.Attr) bool {
+		err = findError(a)
+		return err == nil
+	})
+	if err == nil {
+		return d.handler.Handle(ctx, r)
+	}
+
+	fp := xerrors.Fingerprint(err)
+	now := d.now()
+
+	d.state.mu.Lock()
+	e, ok := d.state.entries[fp]
+	if ok && now.Sub(e.start) < d.window {
+		e.count++
+		d.state.mu.Unlock()
+		return nil
+	}
+	d.state.entries[fp] = &dedupEntry{
+		err:     err,
+		level:   r.Level,
+		handler: d.handler,
+		start:   now,
+		count:   1,
+	}
+	d.state.mu.Unlock()
+
+	if ok {
+		if sErr := d.summarize(ctx, e, now); sErr != nil {
+			return sErr
+		}
+	}
+	return d.handler.Handle(ctx, r)
+}
+
+// WithAttrs implements [slog.Handler].
+func (d *DedupHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &DedupHandler{
+		handler: d.handler.WithAttrs(attrs),
+		window:  d.window,
+		now:     d.now,
+		state:   d.state,
+	}
+}
+
+// WithGroup implements [slog.Handler].
+func (d *DedupHandler) WithGroup(name string) slog.Handler {
+	return &DedupHandler{
+		handler: d.handler.WithGroup(name),
+		window:  d.window,
+		now:     d.now,
+		state:   d.state,
+	}
+}
+
+// Flush emits the summaries of the errors whose window is over and forgets them.
+func (d *DedupHandler) Flush(ctx context.Context) error {
+	return d.flush(ctx, false)
+}
+
+// Close stops the periodic flush and emits the summaries of every error, including the ones whose window is not over.
+// The handler keeps deduplicating the records handled after Close, Flush must then be called by the user.
+// Close is shared by the handlers returned by WithAttrs and WithGroup.
+func (d *DedupHandler) Close(ctx context.Context) error {
+	d.state.closeOnce.Do(func() {
+		close(d.state.stop)
+	})
+	<-d.state.done
+	return d.flush(ctx, true)
+}
+
+// flush emits the summaries of the errors whose window is over, or of every error if all is set, and forgets them.
+func (d *DedupHandler) flush(ctx context.Context, all bool) error {
+	now := d.now()
+
+	d.state.mu.Lock()
+	var expired []*dedupEntry
+	for fp, e := range d.state.entries {
+		if !all && now.Sub(e.start) < d.window {
+			continue
+		}
+		expired = append(expired, e)
+		delete(d.state.entries, fp)
+	}
+	d.state.mu.Unlock()
+
+	for _, e := range expired {
+		if err := d.summarize(ctx, e, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// summarize emits the summary of e if the error occurred more than once during its window.
+func (d *DedupHandler) summarize(ctx context.Context, e *dedupEntry, now time.Time) error {
+	if e.count < 2 {
+		return nil
+	}
+
+	r := slog.NewRecord(now, e.level, fmt.Sprintf("error %q occurred %s times in the last %s", e.err.Error(), formatCount(e.count), d.window), 0)
+	r.AddAttrs(
+		slog.Any("error", e.err),
+		slog.Int("occurrences", e.count),
+		slog.Duration("window", d.window),
+	)
+	return e.handler.Handle(ctx, r)
+}
+
+// findError returns the first error held by a, or by the attributes of a if it is a group.
+func findError(a slog.Attr) error {
+	switch a.Value.Kind() {
+	case slog.KindGroup:
+		for _, ga := range a.Value.Group() {
+			if err := findError(ga); err != nil {
+				return err
+			}
+		}
+	case slog.KindAny, slog.KindLogValuer:
+		if err, ok := a.Value.Any().(error); ok {
+			return err
+		}
+	}
+	return nil
+}
+
+// formatCount formats n with a comma as thousands separator.
+func formatCount(n int) string {
+	s := strconv.Itoa(n)
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}
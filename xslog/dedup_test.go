@@ -0,0 +1,218 @@
+package xslog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/emilien-puget/xerrors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func logLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var ms []map[string]any
+	for _, line := range bytes.Split(buf.Bytes(), []byte{'\n'}) {
+		if len(line) == 0 {
+			continue
+		}
+		var m map[string]any
+		require.NoError(t, json.Unmarshal(line, &m))
+		ms = append(ms, m)
+	}
+	return ms
+}
+
+func dependencyDown() error {
+	return xerrors.Join(errors.New("connection refused"), "calling dependency")
+}
+
+func TestDedupHandler(t *testing.T) {
+	var buf bytes.Buffer
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	h := NewDedupHandler(slog.NewJSONHandler(&buf, nil), &DedupOptions{Now: clock.Now, FlushInterval: -1})
+	logger := slog.New(h)
+
+	for i := 0; i < 4312; i++ {
+		logger.Error("call failed", slog.Any("error", dependencyDown()))
+	}
+	logger.Info("no error")
+
+	lines := logLines(t, &buf)
+	require.Len(t, lines, 2)
+	assert.Equal(t, "call failed", lines[0]["msg"])
+	assert.Equal(t, "no error", lines[1]["msg"])
+
+	buf.Reset()
+	clock.Advance(5 * time.Second)
+	require.NoError(t, h.Flush(context.Background()))
+	assert.Empty(t, buf.String(), "the window is not over")
+
+	clock.Advance(5 * time.Second)
+	require.NoError(t, h.Flush(context.Background()))
+	lines = logLines(t, &buf)
+	require.Len(t, lines, 1)
+	assert.Equal(t, `error "connection refused: calling dependency" occurred 4,312 times in the last 10s`, lines[0]["msg"])
+	assert.Equal(t, "ERROR", lines[0]["level"])
+	assert.EqualValues(t, 4312, lines[0]["occurrences"])
+	require.IsType(t, map[string]any{}, lines[0]["error"])
+	assert.NotEmpty(t, lines[0]["error"].(map[string]any)["stacktrace"])
+
+	buf.Reset()
+	require.NoError(t, h.Flush(context.Background()))
+	assert.Empty(t, buf.String(), "summaries are emitted once")
+}
+
+func TestDedupHandlerWindowOver(t *testing.T) {
+	var buf bytes.Buffer
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	logger := slog.New(NewDedupHandler(slog.NewJSONHandler(&buf, nil), &DedupOptions{
+		Window:        time.Second,
+		Now:           clock.Now,
+		FlushInterval: -1,
+	}))
+
+	logger.Error("call failed", slog.Any("error", dependencyDown()))
+	logger.Error("call failed", slog.Any("error", dependencyDown()))
+	clock.Advance(time.Second)
+	logger.Error("call failed", slog.Any("error", dependencyDown()))
+
+	lines := logLines(t, &buf)
+	require.Len(t, lines, 3)
+	assert.Equal(t, "call failed", lines[0]["msg"])
+	assert.Equal(t, `error "connection refused: calling dependency" occurred 2 times in the last 1s`, lines[1]["msg"])
+	assert.Equal(t, "call failed", lines[2]["msg"])
+}
+
+func TestDedupHandlerDistinctErrors(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewDedupHandler(slog.NewJSONHandler(&buf, nil), nil)
+	t.Cleanup(func() {
+		_ = h.Close(context.Background())
+	})
+	logger := slog.New(h)
+
+	logger.Error("call failed", slog.Any("error", dependencyDown()))
+	logger.Error("call failed", slog.Group("nested", slog.Any("error", errors.New("other"))))
+	logger.Error("call failed", slog.Any("error", dependencyDown()))
+
+	assert.Len(t, logLines(t, &buf), 2)
+}
+
+func TestDedupHandlerConcurrent(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewDedupHandler(slog.NewJSONHandler(&buf, nil), nil)
+	t.Cleanup(func() {
+		_ = h.Close(context.Background())
+	})
+	logger := slog.New(h).With("component", "test")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				logger.Error("call failed", slog.Any("error", dependencyDown()))
+			}
+		}()
+	}
+	wg.Wait()
+
+	lines := logLines(t, &buf)
+	require.Len(t, lines, 1)
+	assert.Equal(t, "test", lines[0]["component"])
+}
+
+// lockedBuffer is a bytes.Buffer safe for concurrent use, written by the periodic flush.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestDedupHandlerPeriodicFlush(t *testing.T) {
+	var buf lockedBuffer
+	h := NewDedupHandler(slog.NewJSONHandler(&buf, nil), &DedupOptions{
+		Window:        10 * time.Millisecond,
+		FlushInterval: 5 * time.Millisecond,
+	})
+	t.Cleanup(func() {
+		_ = h.Close(context.Background())
+	})
+	logger := slog.New(h)
+
+	for i := 0; i < 3; i++ {
+		logger.Error("call failed", slog.Any("error", dependencyDown()))
+	}
+	logger.Error("call failed", slog.Any("error", errors.New("once")))
+
+	assert.Eventually(t, func() bool {
+		return strings.Contains(buf.String(), `occurred 3 times`)
+	}, time.Second, time.Millisecond)
+	assert.Eventually(t, func() bool {
+		h.state.mu.Lock()
+		defer h.state.mu.Unlock()
+		return len(h.state.entries) == 0
+	}, time.Second, time.Millisecond, "the expired errors must be forgotten")
+}
+
+func TestDedupHandlerClose(t *testing.T) {
+	var buf bytes.Buffer
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	h := NewDedupHandler(slog.NewJSONHandler(&buf, nil), &DedupOptions{Now: clock.Now})
+	logger := slog.New(h).With("component", "test")
+
+	logger.Error("call failed", slog.Any("error", dependencyDown()))
+	logger.Error("call failed", slog.Any("error", dependencyDown()))
+
+	buf.Reset()
+	require.NoError(t, h.Close(context.Background()))
+	lines := logLines(t, &buf)
+	require.Len(t, lines, 1, "the summaries are emitted even if the window is not over")
+	assert.Equal(t, `error "connection refused: calling dependency" occurred 2 times in the last 10s`, lines[0]["msg"])
+
+	require.NoError(t, logger.Handler().(*DedupHandler).Close(context.Background()), "Close can be called more than once")
+}
+
+func TestFormatCount(t *testing.T) {
+	assert.Equal(t, "1", formatCount(1))
+	assert.Equal(t, "999", formatCount(999))
+	assert.Equal(t, "4,312", formatCount(4312))
+	assert.Equal(t, "1,000,000", formatCount(1000000))
+}
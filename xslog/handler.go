@@ -0,0 +1,198 @@
+// Package xslog provides [slog.Handler] implementations that understand errors created with xerrors.
+package xslog
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/emilien-puget/xerrors"
+)
+
+// Options configures a Handler.
+type Options struct {
+	// MessageKey is the key of the error message, "message" if empty.
+	MessageKey string
+	// TypeKey is the key of the error type, "type" if empty.
+	TypeKey string
+	// FingerprintKey is the key of the error fingerprint, "fingerprint" if empty.
+	FingerprintKey string
+	// StackKey is the key of the stack trace, "stacktrace" if empty.
+	StackKey string
+	// ValuesKey is the key of the error values, "values" if empty.
+	ValuesKey string
+
+	// StackLevel is the minimum level of a record for the stack trace to be included.
+	// The stack trace is always included if nil.
+	// Errors passed to WithAttrs are expanded without the stack trace when StackLevel is set, as their level is not known yet.
+	StackLevel slog.Leveler
+
+	// FrameFilter reports whether a frame is kept in the stack trace.
+	// Every frame is kept if nil.
+	FrameFilter func(frame xerrors.Frame) bool
+
+	// ExpectedLevel is the maximum level of a record of which every error is expected, see [xerrors.IsExpected],
+	// the level of such a record is lowered to ExpectedLevel, [slog.LevelInfo] if nil.
+	// Set it to a level above every record level, such as slog.Level(math.MaxInt), to leave the level untouched.
+	// Expected errors are always expanded without the stack trace.
+	ExpectedLevel slog.Leveler
+}
+
+// Handler wraps a [slog.Handler] and expands every error-valued attribute, including those nested in groups,
+// into a group holding the message, the type, the fingerprint, the stack trace and the values of the error.
+type Handler struct {
+	handler slog.Handler
+	opts    Options
+}
+
+// NewHandler returns a Handler that wraps h.
+func NewHandler(h slog.Handler, opts *Options) *Handler {
+	if opts == nil {
+		opts = &Options{}
+	}
+	o := *opts
+	if o.MessageKey == "" {
+		o.MessageKey = "message"
+	}
+	if o.TypeKey == "" {
+		o.TypeKey = "type"
+	}
+	if o.FingerprintKey == "" {
+		o.FingerprintKey = "fingerprint"
+	}
+	if o.StackKey == "" {
+		o.StackKey = "stacktrace"
+	}
+	if o.ValuesKey == "" {
+		o.ValuesKey = "values"
+	}
+	if o.ExpectedLevel == nil {
+		o.ExpectedLevel = slog.LevelInfo
+	}
+	return &Handler{
+		handler: h,
+		opts:    o,
+	}
+}
+
+// Enabled implements [slog.Handler].
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+// Handle implements [slog.Handler].
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	level := r.Level
+	if level > h.opts.ExpectedLevel.Level() && onlyExpected(r) {
+		level = h.opts.ExpectedLevel.Level()
+		if !h.handler.Enabled(ctx, level) {
+			return nil
+		}
+	}
+
+	nr := slog.NewRecord(r.Time, level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		nr.AddAttrs(h.expand(a, h.withStack(r.Level)))
+		return true
+	})
+	return h.handler.Handle(ctx, nr)
+}
+
+// WithAttrs implements [slog.Handler].
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	expanded := make([]slog.Attr, len(attrs))
+	for i := range attrs {
+		expanded[i] = h.expand(attrs[i], h.opts.StackLevel == nil)
+	}
+	return &Handler{
+		handler: h.handler.WithAttrs(expanded),
+		opts:    h.opts,
+	}
+}
+
+// WithGroup implements [slog.Handler].
+func (h *Handler) WithGroup(name string) slog.Handler {
+	return &Handler{
+		handler: h.handler.WithGroup(name),
+		opts:    h.opts,
+	}
+}
+
+func (h *Handler) withStack(level slog.Level) bool {
+	return h.opts.StackLevel == nil || level >= h.opts.StackLevel.Level()
+}
+
+func (h *Handler) expand(a slog.Attr, withStack bool) slog.Attr {
+	switch a.Value.Kind() {
+	case slog.KindGroup:
+		group := a.Value.Group()
+		expanded := make([]slog.Attr, len(group))
+		for i := range group {
+			expanded[i] = h.expand(group[i], withStack)
+		}
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(expanded...)}
+	case slog.KindAny, slog.KindLogValuer:
+		if err, ok := a.Value.Any().(error); ok && err != nil {
+			return slog.Attr{Key: a.Key, Value: h.errorValue(err, withStack && !xerrors.IsExpected(err))}
+		}
+	}
+	return a
+}
+
+func (h *Handler) errorValue(err error, withStack bool) slog.Value {
+	info := xerrors.Info(err)
+
+	attrs := make([]slog.Attr, 0, 5)
+	attrs = append(attrs, slog.String(h.opts.MessageKey, err.Error()))
+	if info.Type != "" {
+		attrs = append(attrs, slog.String(h.opts.TypeKey, info.Type))
+	}
+	attrs = append(attrs, slog.String(h.opts.FingerprintKey, info.Fingerprint))
+	if withStack {
+		if frames := h.frames(info.Frames); len(frames) > 0 {
+			attrs = append(attrs, slog.Any(h.opts.StackKey, frames))
+		}
+	}
+	if len(info.OrderedValues) > 0 {
+		attrs = append(attrs, slog.Attr{Key: h.opts.ValuesKey, Value: slog.GroupValue(info.OrderedValues...)})
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+func (h *Handler) frames(frames []xerrors.Frame) []xerrors.Frame {
+	if h.opts.FrameFilter == nil {
+		return frames
+	}
+
+	kept := make([]xerrors.Frame, 0, len(frames))
+	for _, frame := range frames {
+		if h.opts.FrameFilter(frame) {
+			kept = append(kept, frame)
+		}
+	}
+	return kept
+}
+
+// onlyExpected reports whether r holds at least one error and every error it holds is expected.
+func onlyExpected(r slog.Record) bool {
+	found, expected := false, true
+	var visit func(a slog.Attr)
+	visit = func(a slog.Attr) {
+		switch a.Value.Kind() {
+		case slog.KindGroup:
+			for _, ga := range a.Value.Group() {
+				visit(ga)
+			}
+		case slog.KindAny, slog.KindLogValuer:
+			if err, ok := a.Value.Any().(error); ok && err != nil {
+				found = true
+				expected = expected && xerrors.IsExpected(err)
+			}
+		}
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		visit(a)
+		return expected
+	})
+	return found && expected
+}
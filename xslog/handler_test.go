@@ -0,0 +1,170 @@
+package xslog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/emilien-puget/xerrors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func logLine(t *testing.T, buf *bytes.Buffer) map[string]any {
+	t.Helper()
+	var m map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &m))
+	return m
+}
+
+func TestHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewHandler(slog.NewJSONHandler(&buf, nil), nil))
+
+	err := xerrors.New("error")
+	logger.Info("test", slog.Any("error", err))
+
+	m := logLine(t, &buf)
+	require.IsType(t, map[string]any{}, m["error"])
+	a := m["error"].(map[string]any)
+	assert.Equal(t, "error", a["message"])
+	assert.Equal(t, "*xerrors.errorString", a["type"])
+	require.IsType(t, []any{}, a["stacktrace"])
+	stack := a["stacktrace"].([]any)
+	require.Len(t, stack, 3)
+	assert.Equal(t, "github.com/emilien-puget/xerrors/xslog.TestHandler", stack[0].(map[string]any)["function"])
+	assert.NotContains(t, a, "values")
+}
+
+func TestHandlerForeignError(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewHandler(slog.NewJSONHandler(&buf, nil), nil))
+
+	err := errors.New("plouf")
+	logger.Info("test", slog.Any("error", err))
+
+	m := logLine(t, &buf)
+	assert.Equal(t, map[string]any{
+		"message":     "plouf",
+		"type":        "*errors.errorString",
+		"fingerprint": xerrors.Fingerprint(err),
+	}, m["error"])
+}
+
+func TestHandlerNestedGroup(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewHandler(slog.NewJSONHandler(&buf, nil), &Options{
+		MessageKey:     "msg",
+		TypeKey:        "kind",
+		FingerprintKey: "fp",
+		StackKey:       "stack",
+		ValuesKey:      "context",
+	}))
+
+	err := xerrors.Join(errors.New("plouf"), xerrors.WithValue("key", "value"))
+	logger.With(slog.Any("bound", err)).Info("test", slog.Group("request", slog.Any("error", err), slog.Int("status", 500)))
+
+	m := logLine(t, &buf)
+	require.IsType(t, map[string]any{}, m["request"])
+	req := m["request"].(map[string]any)
+	assert.EqualValues(t, 500, req["status"])
+	for _, v := range []any{req["error"], m["bound"]} {
+		require.IsType(t, map[string]any{}, v)
+		a := v.(map[string]any)
+		assert.Equal(t, "plouf", a["msg"])
+		assert.Equal(t, "*errors.errorString", a["kind"])
+		assert.Equal(t, map[string]any{"key": "value"}, a["context"])
+		assert.NotEmpty(t, a["stack"])
+		assert.Equal(t, xerrors.Fingerprint(err), a["fp"])
+	}
+}
+
+func TestHandlerStackLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewHandler(slog.NewJSONHandler(&buf, nil), &Options{StackLevel: slog.LevelError}))
+	err := xerrors.New("error")
+
+	logger.Warn("test", slog.Any("error", err))
+	a := logLine(t, &buf)["error"].(map[string]any)
+	assert.NotContains(t, a, "stacktrace")
+
+	buf.Reset()
+	logger.Error("test", slog.Any("error", err))
+	a = logLine(t, &buf)["error"].(map[string]any)
+	assert.Contains(t, a, "stacktrace")
+}
+
+func TestHandlerFrameFilter(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewHandler(slog.NewJSONHandler(&buf, nil), &Options{
+		FrameFilter: func(frame xerrors.Frame) bool {
+			return !strings.HasPrefix(frame.Function, "runtime.") && !strings.HasPrefix(frame.Function, "testing.")
+		},
+	}))
+
+	logger.Info("test", slog.Any("error", xerrors.New("error")))
+
+	a := logLine(t, &buf)["error"].(map[string]any)
+	require.Len(t, a["stacktrace"], 1)
+}
+
+func TestHandlerExpected(t *testing.T) {
+	for name, test := range map[string]struct {
+		attrs     []any
+		wantLevel string
+	}{
+		"expected": {
+			attrs:     []any{slog.Any("error", xerrors.Expected(xerrors.New("user not found")))},
+			wantLevel: "INFO",
+		},
+		"canceled": {
+			attrs:     []any{slog.Group("request", slog.Any("error", xerrors.Join(context.Canceled, "aborted")))},
+			wantLevel: "INFO",
+		},
+		"mixed": {
+			attrs:     []any{slog.Any("error", xerrors.Expected(xerrors.New("user not found"))), slog.Any("cause", xerrors.New("error"))},
+			wantLevel: "ERROR",
+		},
+		"no_error": {
+			attrs:     []any{slog.String("user", "bob")},
+			wantLevel: "ERROR",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(NewHandler(slog.NewJSONHandler(&buf, nil), nil))
+
+			logger.Error("test", test.attrs...)
+
+			m := logLine(t, &buf)
+			assert.Equal(t, test.wantLevel, m["level"])
+		})
+	}
+}
+
+func TestHandlerExpectedNoStack(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewHandler(slog.NewJSONHandler(&buf, nil), &Options{ExpectedLevel: slog.Level(math.MaxInt)}))
+
+	logger.Error("test", slog.Any("error", xerrors.Expected(xerrors.New("user not found"))))
+
+	m := logLine(t, &buf)
+	assert.Equal(t, "ERROR", m["level"], "the level is left untouched")
+	a := m["error"].(map[string]any)
+	assert.Equal(t, "user not found", a["message"])
+	assert.NotContains(t, a, "stacktrace")
+}
+
+func TestHandlerExpectedDisabled(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewHandler(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelWarn}), nil))
+
+	logger.Error("test", slog.Any("error", xerrors.Expected(xerrors.New("user not found"))))
+
+	assert.Empty(t, buf.String())
+}